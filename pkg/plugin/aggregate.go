@@ -0,0 +1,308 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// dimensionCustomField groups by the custom ticket field named by groupByField
+const dimensionCustomField = "custom_field"
+
+// otherGroup collects the groups beyond the top N
+const otherGroup = "other"
+
+// groupDimension names the frame columns a group-by dimension reads
+type groupDimension struct {
+	// Column holds the group key of a record
+	Column string
+	// LabelColumn optionally holds a display name for the key, e.g. assignee names
+	LabelColumn string
+	// Multi splits space separated values so a record joins several groups
+	Multi bool
+}
+
+// groupDimensions are the named group-by dimensions of ticket, user and
+// organization frames. Any other groupBy is read as a frame column.
+var groupDimensions = map[string]groupDimension{
+	"status":       {Column: "status"},
+	"priority":     {Column: "priority"},
+	"assignee":     {Column: "assignee_id", LabelColumn: "assignee_name"},
+	"group":        {Column: "group_id", LabelColumn: "group_name"},
+	"organization": {Column: "organization_id", LabelColumn: "organization_name"},
+	"brand":        {Column: "brand_id"},
+	"channel":      {Column: "via_channel"},
+	"tag":          {Column: "tags", Multi: true},
+}
+
+// groupByOptions controls how record frames are grouped and aggregated
+type groupByOptions struct {
+	GroupBy string
+	// Field is the custom field id or title of the custom_field dimension
+	Field       interface{}
+	Aggregation string
+	ValueField  string
+	Percentile  float64
+	// TopN keeps the N largest groups and merges the rest into "other" (0 keeps all)
+	TopN int
+}
+
+// parseGroupByOptions reads the group-by options of a query
+func parseGroupByOptions(model *QueryModel) (*groupByOptions, error) {
+	opts := &groupByOptions{
+		Aggregation: aggregationCount,
+		Percentile:  95,
+	}
+	opts.GroupBy = model.GroupBy
+	opts.Field = model.GroupByField
+	if opts.GroupBy == dimensionCustomField && opts.Field == nil {
+		return nil, fmt.Errorf("groupByField is required to group by custom field")
+	}
+	if model.Aggregation != "" {
+		opts.Aggregation = model.Aggregation
+	}
+	opts.ValueField = model.ValueField
+	if model.Percentile != nil {
+		opts.Percentile = *model.Percentile
+	}
+	if model.TopN < 0 {
+		return nil, fmt.Errorf("topN must not be negative")
+	}
+	opts.TopN = model.TopN
+
+	switch opts.Aggregation {
+	case aggregationCount:
+	case aggregationDistinctCount, aggregationSum, aggregationAvg, aggregationPercentile:
+		if opts.ValueField == "" {
+			return nil, fmt.Errorf("valueField is required for %s", opts.Aggregation)
+		}
+	default:
+		return nil, fmt.Errorf("unknown aggregation: %s", opts.Aggregation)
+	}
+	if opts.Percentile < 0 || opts.Percentile > 100 {
+		return nil, fmt.Errorf("percentile must be between 0 and 100")
+	}
+	return opts, nil
+}
+
+// withGroupByCustomField adds the custom field a query groups by to its
+// customFields, so ticket frames carry its column
+func withGroupByCustomField(model *QueryModel) {
+	if model.GroupBy != dimensionCustomField {
+		return
+	}
+	withCustomField(model, model.GroupByField)
+}
+
+// withCustomField adds a custom field id or title to the customFields of a
+// query unless they already include it
+func withCustomField(model *QueryModel, field interface{}) {
+	switch field.(type) {
+	case float64, string:
+	default:
+		return
+	}
+	for _, choice := range model.CustomFields {
+		if choice == field || choice == allCustomFields {
+			return
+		}
+	}
+	model.CustomFields = append(model.CustomFields, field)
+}
+
+// groupByResponse replaces the record frames of resp with one row per group
+func (ds *Datasource) groupByResponse(ctx context.Context, model *QueryModel, resp *backend.DataResponse) *backend.DataResponse {
+	opts, err := parseGroupByOptions(model)
+	if err != nil {
+		return &backend.DataResponse{
+			Error:  err,
+			Status: backend.StatusBadRequest,
+		}
+	}
+
+	dimension, ok := groupDimensions[opts.GroupBy]
+	if !ok {
+		dimension = groupDimension{Column: opts.GroupBy}
+	}
+	if opts.GroupBy == dimensionCustomField {
+		fields, err := ds.listTicketFields(ctx)
+		if err != nil {
+			return &backend.DataResponse{
+				Error: fmt.Errorf("failed to fetch ticket fields: %w", err),
+			}
+		}
+		selected, err := selectCustomFields(fields, []interface{}{opts.Field})
+		if err != nil {
+			return &backend.DataResponse{
+				Error:  err,
+				Status: backend.StatusBadRequest,
+			}
+		}
+		dimension = groupDimension{Column: selected[0].Column}
+	}
+
+	frame, err := groupByFrame(resp.Frames, dimension, opts)
+	if err != nil {
+		return &backend.DataResponse{
+			Error:  err,
+			Status: backend.StatusBadRequest,
+		}
+	}
+	carryNotices(frame, resp.Frames)
+	return &backend.DataResponse{
+		Frames: data.Frames{frame},
+	}
+}
+
+// recordGroup accumulates the records of one group
+type recordGroup struct {
+	Key      string
+	Label    string
+	Rows     int
+	Values   []float64
+	Distinct map[string]bool
+}
+
+// merge adds the records of other to g
+func (g *recordGroup) merge(other *recordGroup) {
+	g.Rows += other.Rows
+	g.Values = append(g.Values, other.Values...)
+	for value := range other.Distinct {
+		g.Distinct[value] = true
+	}
+}
+
+// aggregate reduces the records of the group
+func (g *recordGroup) aggregate(opts *groupByOptions) *float64 {
+	var v float64
+	switch opts.Aggregation {
+	case aggregationCount:
+		v = float64(g.Rows)
+	case aggregationDistinctCount:
+		v = float64(len(g.Distinct))
+	default:
+		return aggregateValues(opts.Aggregation, g.Values, opts.Percentile)
+	}
+	return &v
+}
+
+// groupByFrame groups the rows of the first frame holding the dimension
+// column and aggregates each group. Groups are sorted by descending value;
+// with TopN the smallest groups are merged into a trailing "other" group.
+func groupByFrame(frames data.Frames, dimension groupDimension, opts *groupByOptions) (*data.Frame, error) {
+	var source *data.Frame
+	var column *data.Field
+	for _, frame := range frames {
+		if field, _ := frame.FieldByName(dimension.Column); field != nil {
+			source, column = frame, field
+			break
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("unknown group by field: %s", opts.GroupBy)
+	}
+
+	var labelColumn, valueColumn *data.Field
+	if dimension.LabelColumn != "" {
+		labelColumn, _ = source.FieldByName(dimension.LabelColumn)
+	}
+	if opts.Aggregation != aggregationCount {
+		valueColumn, _ = source.FieldByName(opts.ValueField)
+		if valueColumn == nil {
+			return nil, fmt.Errorf("unknown value field: %s", opts.ValueField)
+		}
+		numeric := opts.Aggregation == aggregationDistinctCount || valueColumn.Type().Numeric()
+		if !numeric {
+			return nil, fmt.Errorf("value field %s is not numeric", opts.ValueField)
+		}
+	}
+
+	groups := map[string]*recordGroup{}
+	for i := 0; i < source.Rows(); i++ {
+		keys := []string{seriesLabelAt(column, i)}
+		if dimension.Multi && keys[0] != "none" {
+			keys = strings.Fields(keys[0])
+		}
+		label := ""
+		if labelColumn != nil {
+			if name, ok := labelColumn.ConcreteAt(i); ok {
+				label = fmt.Sprint(name)
+			}
+		}
+
+		for _, key := range keys {
+			group := groups[key]
+			if group == nil {
+				group = &recordGroup{Key: key, Label: label, Distinct: map[string]bool{}}
+				if group.Label == "" {
+					group.Label = key
+				}
+				groups[key] = group
+			}
+			group.Rows++
+			if valueColumn == nil {
+				continue
+			}
+			if opts.Aggregation == aggregationDistinctCount {
+				if value, ok := valueColumn.ConcreteAt(i); ok {
+					group.Distinct[fmt.Sprint(value)] = true
+				}
+				continue
+			}
+			value, err := valueColumn.NullableFloatAt(i)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", opts.ValueField, err)
+			}
+			if value != nil {
+				group.Values = append(group.Values, *value)
+			}
+		}
+	}
+
+	type result struct {
+		group *recordGroup
+		value *float64
+	}
+	results := make([]result, 0, len(groups))
+	for _, group := range groups {
+		results = append(results, result{group: group, value: group.aggregate(opts)})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i].value, results[j].value
+		if (a == nil) != (b == nil) {
+			return b == nil
+		}
+		if a != nil && *a != *b {
+			return *a > *b
+		}
+		return results[i].group.Label < results[j].group.Label
+	})
+
+	if opts.TopN > 0 && len(results) > opts.TopN {
+		other := &recordGroup{Key: otherGroup, Label: otherGroup, Distinct: map[string]bool{}}
+		for _, r := range results[opts.TopN:] {
+			other.merge(r.group)
+		}
+		results = append(results[:opts.TopN], result{group: other, value: other.aggregate(opts)})
+	}
+
+	// Custom field groups are named after the field
+	name := opts.GroupBy
+	if name == dimensionCustomField {
+		name = dimension.Column
+	}
+	frame := data.NewFrame("aggregation")
+	frame.Fields = append(frame.Fields,
+		data.NewField(name, nil, []string{}),
+		data.NewField(aggregationName(opts.Aggregation, opts.ValueField, opts.Percentile), nil, []*float64{}),
+	)
+	for _, r := range results {
+		frame.AppendRow(r.group.Label, r.value)
+	}
+	frame.Meta = &data.FrameMeta{Type: data.FrameTypeNumericLong}
+	return frame, nil
+}
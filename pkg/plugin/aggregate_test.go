@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aggregateDatasource(t *testing.T) *Datasource {
+	return newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/tickets.json":
+			fmt.Fprint(w, `{"tickets":[
+				{"id":1,"status":"open","assignee_id":8,"requester_id":1,"tags":["vip","billing"],"custom_fields":[{"id":100,"value":"eu"},{"id":101,"value":5}]},
+				{"id":2,"status":"open","assignee_id":8,"requester_id":2,"tags":["billing"],"custom_fields":[{"id":100,"value":"us"},{"id":101,"value":7}]},
+				{"id":3,"status":"pending","assignee_id":9,"requester_id":1,"custom_fields":[{"id":100,"value":"eu"},{"id":101,"value":1}]},
+				{"id":4,"status":"solved","requester_id":3,"tags":["vip"],"custom_fields":[{"id":100,"value":"eu"}]},
+				{"id":5,"status":"new","requester_id":3}],
+				"users":[{"id":1,"name":"Cai"},{"id":2,"name":"Dev"},{"id":3,"name":"Eli"},{"id":8,"name":"Ana"},{"id":9,"name":"Ben"}],
+				"meta":{"has_more":false}}`)
+		case "/api/v2/ticket_fields.json":
+			fmt.Fprint(w, `{"ticket_fields":[
+				{"id":100,"type":"text","title":"Region","active":true,"removable":true},
+				{"id":101,"type":"integer","title":"Seats","active":true,"removable":true}]}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+}
+
+// aggregationRows renders an aggregation frame as "label=value" rows
+func aggregationRows(t *testing.T, frame *data.Frame) []string {
+	t.Helper()
+	require.Len(t, frame.Fields, 2)
+	rows := make([]string, frame.Rows())
+	for i := range rows {
+		value := "null"
+		if v := frame.Fields[1].At(i).(*float64); v != nil {
+			value = fmt.Sprintf("%g", *v)
+		}
+		rows[i] = fmt.Sprintf("%s=%s", frame.Fields[0].At(i), value)
+	}
+	return rows
+}
+
+func TestGroupBy(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		query string
+		rows  []string
+	}{
+		{"count by status", `{"groupBy":"status"}`, []string{"open=2", "new=1", "pending=1", "solved=1"}},
+		{"top n with other", `{"groupBy":"status","topN":1}`, []string{"open=2", "other=3"}},
+		{"assignee names", `{"groupBy":"assignee"}`, []string{"Ana=2", "none=2", "Ben=1"}},
+		{"tags split", `{"groupBy":"tag"}`, []string{"billing=2", "none=2", "vip=2"}},
+		{"distinct requesters", `{"groupBy":"status","aggregation":"distinct_count","valueField":"requester_id"}`, []string{"open=2", "new=1", "pending=1", "solved=1"}},
+		{"custom field dimension", `{"groupBy":"custom_field","groupByField":100}`, []string{"eu=3", "none=1", "us=1"}},
+		{"sum of custom field", `{"groupBy":"status","aggregation":"sum","valueField":"Seats","customFields":["Seats"]}`, []string{"open=12", "pending=1", "new=0", "solved=0"}},
+		{"avg other bucket", `{"groupBy":"status","aggregation":"avg","valueField":"Seats","customFields":["Seats"],"topN":1}`, []string{"open=6", "other=1"}},
+		{"percentile", `{"groupBy":"assignee","aggregation":"percentile","percentile":50,"valueField":"Seats","customFields":["Seats"]}`, []string{"Ana=6", "Ben=1", "none=null"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ds := aggregateDatasource(t)
+			query := fmt.Sprintf(`{"queryType":"tickets",%s`, tc.query[1:])
+			resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(query)})
+			require.NoError(t, resp.Error)
+			require.Len(t, resp.Frames, 1)
+			assert.Equal(t, data.FrameTypeNumericLong, resp.Frames[0].Meta.Type)
+			assert.Equal(t, tc.rows, aggregationRows(t, resp.Frames[0]))
+		})
+	}
+}
+
+func TestGroupBy_CustomFieldColumnName(t *testing.T) {
+	ds := aggregateDatasource(t)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","groupBy":"custom_field","groupByField":"Region"}`)})
+	require.NoError(t, resp.Error)
+	assert.Equal(t, "Region", resp.Frames[0].Fields[0].Name)
+}
+
+func TestGroupBy_InvalidOptions(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		err   string
+	}{
+		{`{"queryType":"tickets","groupBy":"status","aggregation":"sum"}`, "valueField is required for sum"},
+		{`{"queryType":"tickets","groupBy":"status","aggregation":"avg","valueField":"subject"}`, "value field subject is not numeric"},
+		{`{"queryType":"tickets","groupBy":"mood"}`, "unknown group by field: mood"},
+		{`{"queryType":"tickets","groupBy":"custom_field"}`, "groupByField is required to group by custom field"},
+	} {
+		ds := aggregateDatasource(t)
+		resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(tc.query)})
+		assert.EqualError(t, resp.Error, tc.err, tc.query)
+		assert.Equal(t, backend.StatusBadRequest, resp.Status, tc.query)
+	}
+}
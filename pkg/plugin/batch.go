@@ -23,8 +23,12 @@ type BatchQueryResponse struct {
 	Errors  []string      `json:"errors,omitempty"`
 }
 
-// ExecuteBatchQuery executes multiple queries in parallel
+// ExecuteBatchQuery executes multiple queries in parallel. Cancelling ctx
+// cancels every query still in flight.
 func (ds *Datasource) ExecuteBatchQuery(ctx context.Context, req *BatchQueryRequest) (*BatchQueryResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	var wg sync.WaitGroup
 	results := make([]interface{}, len(req.Queries))
 	errors := make([]string, len(req.Queries))
@@ -40,11 +44,11 @@ func (ds *Datasource) ExecuteBatchQuery(ctx context.Context, req *BatchQueryRequ
 
 			switch q.QueryType {
 			case "tickets":
-				result, err = ds.zendeskClient.GetTickets(q.Params)
+				result, err = ds.zendeskClient.GetTickets(ctx, q.Params)
 			case "users":
-				result, err = ds.zendeskClient.GetUsers(q.Params)
+				result, err = ds.zendeskClient.GetUsers(ctx, q.Params)
 			case "organizations":
-				result, err = ds.zendeskClient.GetOrganizations(q.Params)
+				result, err = ds.zendeskClient.GetOrganizations(ctx, q.Params)
 			default:
 				err = fmt.Errorf("unknown query type: %s", q.QueryType)
 			}
@@ -61,6 +65,10 @@ func (ds *Datasource) ExecuteBatchQuery(ctx context.Context, req *BatchQueryRequ
 
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("batch query cancelled: %w", err)
+	}
+
 	// Filter out nil results
 	filteredResults := make([]interface{}, 0)
 	for _, r := range results {
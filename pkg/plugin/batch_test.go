@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteBatchQuery_Cancelled(t *testing.T) {
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: json.RawMessage(`{"subdomain":"test","email":"test@example.com"}`),
+		DecryptedSecureJSONData: map[string]string{
+			"apiToken": "test-token",
+		},
+	}
+	instance, err := NewDatasource(context.Background(), settings)
+	require.NoError(t, err)
+	ds := instance.(*Datasource)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = ds.ExecuteBatchQuery(ctx, &BatchQueryRequest{
+		Queries: []QueryRequest{{QueryType: "tickets"}, {QueryType: "users"}},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestExecuteBatchQuery_CancelledMidFlight(t *testing.T) {
+	var started, cancelled int32
+	arrived := make(chan struct{}, 2)
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&started, 1)
+		arrived <- struct{}{}
+		select {
+		case <-r.Context().Done():
+			atomic.AddInt32(&cancelled, 1)
+		case <-time.After(10 * time.Second):
+			w.Write([]byte(`{}`))
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// Cancel once both queries are blocked in the server
+		<-arrived
+		<-arrived
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := ds.ExecuteBatchQuery(ctx, &BatchQueryRequest{
+		Queries: []QueryRequest{{QueryType: "tickets"}, {QueryType: "users"}},
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&started))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&cancelled) == 2
+	}, time.Second, 10*time.Millisecond)
+}
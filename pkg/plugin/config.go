@@ -1,13 +1,198 @@
 package plugin
 
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// AuthMode selects how the datasource authenticates against Zendesk
+type AuthMode string
+
+const (
+	// AuthModeAPIToken uses an agent email and API token (default)
+	AuthModeAPIToken AuthMode = "apiToken"
+	// AuthModeOAuthToken uses a pre-issued OAuth access token
+	AuthModeOAuthToken AuthMode = "oauthToken"
+	// AuthModeOAuthClientCredentials exchanges OAuth client credentials for access tokens
+	AuthModeOAuthClientCredentials AuthMode = "oauthClientCredentials"
+)
+
 // Config represents the datasource configuration
 type Config struct {
-	Subdomain string `json:"subdomain"`
-	Email     string `json:"email"`
+	Subdomain string   `json:"subdomain"`
+	Email     string   `json:"email"`
+	AuthMode  AuthMode `json:"authMode,omitempty"`
+
+	// BaseURL overrides https://{subdomain}.zendesk.com/api/v2, e.g. for host-mapped domains
+	BaseURL string `json:"baseUrl,omitempty"`
+	// ProxyURL routes Zendesk requests through an HTTP(S) egress proxy
+	ProxyURL string `json:"proxyUrl,omitempty"`
+
+	// OAuth client credentials settings; the token URL defaults to the subdomain's /oauth/tokens
+	OAuthTokenURL string `json:"oauthTokenUrl,omitempty"`
+	OAuthClientID string `json:"oauthClientId,omitempty"`
+	OAuthScope    string `json:"oauthScope,omitempty"`
+
+	// Retry settings; zero values fall back to zendesk.DefaultRetryPolicy
+	RetryMaxAttempts   int      `json:"retryMaxAttempts,omitempty"`
+	RetryBaseDelayMs   int      `json:"retryBaseDelayMs,omitempty"`
+	RetryMaxDelayMs    int      `json:"retryMaxDelayMs,omitempty"`
+	RetryJitter        *float64 `json:"retryJitter,omitempty"`
+	RetryNonIdempotent bool     `json:"retryNonIdempotent,omitempty"`
 }
 
-// SecureConfig represents secure configuration (API token)
+// SecureConfig represents secure configuration (API token and OAuth secrets)
 type SecureConfig struct {
-	APIToken string `json:"apiToken"`
+	APIToken          string `json:"apiToken"`
+	OAuthAccessToken  string `json:"oauthAccessToken"`
+	OAuthClientSecret string `json:"oauthClientSecret"`
+}
+
+// Authenticator returns the Zendesk authenticator for the configured auth
+// mode, or an error when a credential it needs is missing
+func (c *Config) Authenticator(secure SecureConfig) (zendesk.Authenticator, error) {
+	switch c.AuthMode {
+	case "", AuthModeAPIToken:
+		if secure.APIToken == "" {
+			return nil, fmt.Errorf("API token is required")
+		}
+		return &zendesk.APITokenAuth{Email: c.Email, APIToken: secure.APIToken}, nil
+	case AuthModeOAuthToken:
+		if secure.OAuthAccessToken == "" {
+			return nil, fmt.Errorf("OAuth access token is required")
+		}
+		return &zendesk.BearerTokenAuth{AccessToken: secure.OAuthAccessToken}, nil
+	case AuthModeOAuthClientCredentials:
+		if c.OAuthClientID == "" || secure.OAuthClientSecret == "" {
+			return nil, fmt.Errorf("OAuth client ID and client secret are required")
+		}
+		tokenURL := c.OAuthTokenURL
+		if tokenURL == "" && c.BaseURL != "" {
+			tokenURL = strings.TrimSuffix(strings.TrimSuffix(c.BaseURL, "/"), "/api/v2") + "/oauth/tokens"
+		} else if tokenURL == "" {
+			tokenURL = zendesk.DefaultTokenURL(c.Subdomain)
+		}
+		return &zendesk.ClientCredentialsAuth{
+			TokenURL:     tokenURL,
+			ClientID:     c.OAuthClientID,
+			ClientSecret: secure.OAuthClientSecret,
+			Scope:        c.OAuthScope,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown auth mode: %s", c.AuthMode)
+	}
+}
+
+// RetryPolicy returns the client retry policy described by the settings
+func (c *Config) RetryPolicy() zendesk.RetryPolicy {
+	policy := zendesk.DefaultRetryPolicy()
+	if c.RetryMaxAttempts > 0 {
+		policy.MaxAttempts = c.RetryMaxAttempts
+	}
+	if c.RetryBaseDelayMs > 0 {
+		policy.BaseDelay = time.Duration(c.RetryBaseDelayMs) * time.Millisecond
+	}
+	if c.RetryMaxDelayMs > 0 {
+		policy.MaxDelay = time.Duration(c.RetryMaxDelayMs) * time.Millisecond
+	}
+	if c.RetryJitter != nil && *c.RetryJitter >= 0 && *c.RetryJitter <= 1 {
+		policy.Jitter = *c.RetryJitter
+	}
+	if c.RetryNonIdempotent {
+		policy.IdempotentOnly = false
+	}
+	return policy
+}
+
+// Validate checks the connection settings
+func (c *Config) Validate() error {
+	if c.Subdomain == "" && c.BaseURL == "" {
+		return fmt.Errorf("subdomain or base URL is required")
+	}
+	if c.BaseURL != "" {
+		if _, err := parseHTTPURL(c.BaseURL); err != nil {
+			return fmt.Errorf("invalid base URL: %w", err)
+		}
+	}
+	if c.ProxyURL != "" {
+		if _, err := parseHTTPURL(c.ProxyURL); err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+	}
+	return nil
 }
 
+// parseHTTPURL parses an absolute http or https URL
+func parseHTTPURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute http(s) URL", raw)
+	}
+	return u, nil
+}
+
+// ClientOptions returns the Zendesk client options for the connection
+// settings, building the transport from the Grafana HTTP client options
+// (timeouts, TLS CA bundle and client certificates, secure socks proxy)
+func (c *Config) ClientOptions(ctx context.Context, settings backend.DataSourceInstanceSettings, auth zendesk.Authenticator) ([]zendesk.ClientOption, error) {
+	httpOpts, err := settings.HTTPClientOptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTTP client options: %w", err)
+	}
+	// Zendesk credentials are set by the authenticator
+	httpOpts.BasicAuth = nil
+
+	if c.ProxyURL != "" {
+		proxyURL, err := parseHTTPURL(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		configure := httpOpts.ConfigureTransport
+		httpOpts.ConfigureTransport = func(opts httpclient.Options, transport *http.Transport) {
+			if configure != nil {
+				configure(opts, transport)
+			}
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	transport, err := httpclient.GetTransport(httpOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP transport: %w", err)
+	}
+
+	// The timeout applies to each attempt; rate limit waits and retries
+	// are bounded by the query context instead
+	timeout := zendesk.DefaultTimeout
+	if httpOpts.Timeouts != nil && httpOpts.Timeouts.Timeout > 0 {
+		timeout = httpOpts.Timeouts.Timeout
+	}
+
+	// Token exchanges go through the same proxy and TLS settings
+	if cc, ok := auth.(*zendesk.ClientCredentialsAuth); ok && cc.HTTPClient == nil {
+		cc.HTTPClient = &http.Client{Transport: transport, Timeout: timeout}
+	}
+
+	opts := []zendesk.ClientOption{
+		zendesk.WithAuthenticator(auth),
+		zendesk.WithRetryPolicy(c.RetryPolicy()),
+		zendesk.WithTransport(transport),
+		zendesk.WithTimeout(timeout),
+	}
+	if c.BaseURL != "" {
+		opts = append(opts, zendesk.WithBaseURL(c.BaseURL))
+	}
+	return opts, nil
+}
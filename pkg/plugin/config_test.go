@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+func TestConfig_RetryPolicyDefaults(t *testing.T) {
+	config := Config{}
+	assert.Equal(t, zendesk.DefaultRetryPolicy(), config.RetryPolicy())
+}
+
+func TestConfig_RetryPolicyOverrides(t *testing.T) {
+	var config Config
+	err := json.Unmarshal([]byte(`{"retryMaxAttempts":5,"retryBaseDelayMs":200,"retryMaxDelayMs":2000,"retryJitter":0,"retryNonIdempotent":true}`), &config)
+	require.NoError(t, err)
+
+	policy := config.RetryPolicy()
+	assert.Equal(t, 5, policy.MaxAttempts)
+	assert.Equal(t, 200*time.Millisecond, policy.BaseDelay)
+	assert.Equal(t, 2*time.Second, policy.MaxDelay)
+	assert.Equal(t, 0.0, policy.Jitter)
+	assert.False(t, policy.IdempotentOnly)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.Error(t, (&Config{}).Validate())
+	assert.NoError(t, (&Config{Subdomain: "test"}).Validate())
+	assert.NoError(t, (&Config{BaseURL: "https://support.example.com/api/v2"}).Validate())
+	assert.Error(t, (&Config{BaseURL: "support.example.com"}).Validate())
+	assert.Error(t, (&Config{Subdomain: "test", ProxyURL: "://bad"}).Validate())
+}
+
+func TestNewDatasource_BaseURLAndProxy(t *testing.T) {
+	var requested string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		fmt.Fprint(w, `{"user":{"id":1}}`)
+	}))
+	defer proxy.Close()
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: json.RawMessage(fmt.Sprintf(`{"baseUrl":"http://support.example.invalid/api/v2","proxyUrl":%q,"timeout":5}`, proxy.URL)),
+		DecryptedSecureJSONData: map[string]string{
+			"apiToken": "test-token",
+		},
+	}
+	instance, err := NewDatasource(context.Background(), settings)
+	require.NoError(t, err)
+
+	result, err := instance.(*Datasource).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, backend.HealthStatusOk, result.Status)
+	assert.Equal(t, "http://support.example.invalid/api/v2/users/me.json", requested)
+}
+
+func TestNewDatasource_TLSCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"user":{"id":1}}`)
+	}))
+	defer server.Close()
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	check := func(jsonData string, secure map[string]string) backend.HealthStatus {
+		secure["apiToken"] = "test-token"
+		instance, err := NewDatasource(context.Background(), backend.DataSourceInstanceSettings{
+			JSONData:                json.RawMessage(jsonData),
+			DecryptedSecureJSONData: secure,
+		})
+		require.NoError(t, err)
+		result, err := instance.(*Datasource).CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		return result.Status
+	}
+
+	baseURL := server.URL + "/api/v2"
+	// The test server certificate is only trusted through the CA bundle
+	assert.Equal(t, backend.HealthStatusError, check(fmt.Sprintf(`{"baseUrl":%q,"retryMaxAttempts":1}`, baseURL), map[string]string{}))
+	assert.Equal(t, backend.HealthStatusOk, check(fmt.Sprintf(`{"baseUrl":%q,"tlsAuthWithCACert":true}`, baseURL), map[string]string{"tlsCACert": string(caCert)}))
+	assert.Equal(t, backend.HealthStatusOk, check(fmt.Sprintf(`{"baseUrl":%q,"tlsSkipVerify":true}`, baseURL), map[string]string{}))
+}
@@ -0,0 +1,296 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// csatBreakdownLabels maps the supported csat breakdowns to their series label
+var csatBreakdownLabels = map[string]string{
+	"group":    "group_id",
+	"assignee": "assignee_id",
+	"brand":    "brand_id",
+}
+
+// csatNameLabels maps the supported csat breakdowns to the label of their series name
+var csatNameLabels = map[string]string{
+	"group":    "group_name",
+	"assignee": "assignee_name",
+	"brand":    "brand_name",
+}
+
+// csatScores are the score filters accepted by the satisfaction ratings API
+var csatScores = []string{
+	"offered", "unoffered", "received", "received_with_comment", "received_without_comment",
+	"good", "good_with_comment", "good_without_comment", "bad", "bad_with_comment", "bad_without_comment",
+}
+
+// csatResult holds satisfaction ratings and, for brand breakdowns, the brand
+// of each rated ticket
+type csatResult struct {
+	Ratings []zendesk.SatisfactionRating
+	Brands  map[int64]*int64
+}
+
+// csatSourceFrame returns an empty frame of offered ratings, each counted as
+// good, bad and offered, bucketed into the csat series
+func csatSourceFrame() *data.Frame {
+	return data.NewFrame("",
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("good", nil, []float64{}),
+		data.NewField("bad", nil, []float64{}),
+		data.NewField("offered", nil, []float64{}),
+	)
+}
+
+// queryCSAT handles satisfaction rating queries over the query time range.
+// score filters the ratings; breakdown splits the series by group, assignee or brand.
+func (ds *Datasource) queryCSAT(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	opts := zendesk.SatisfactionRatingOptions{
+		StartTime: query.TimeRange.From,
+		EndTime:   query.TimeRange.To,
+	}
+	opts.Score = model.Score
+	breakdown := model.Breakdown
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("csat:%s:%s:%d:%d", opts.Score, breakdown, opts.StartTime.Unix(), opts.EndTime.Unix())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if result, ok := cached.(*csatResult); ok {
+			return ds.csatResponse(ctx, result, breakdown, query)
+		}
+	}
+
+	// Fetch from API
+	ratings, err := ds.zendeskClient.ListSatisfactionRatings(ctx, opts, maxQueryRows)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch satisfaction ratings: %w", err),
+		}
+	}
+	result := &csatResult{Ratings: ratings}
+
+	// Ratings do not carry the brand, it is read from their tickets
+	if breakdown == "brand" {
+		ids := make([]int64, 0, len(ratings))
+		for _, rating := range ratings {
+			ids = append(ids, rating.TicketID)
+		}
+		tickets, err := ds.zendeskClient.ShowManyTickets(ctx, ids)
+		if err != nil {
+			return &backend.DataResponse{
+				Error: fmt.Errorf("failed to fetch rated tickets: %w", err),
+			}
+		}
+		result.Brands = make(map[int64]*int64, len(tickets))
+		for _, ticket := range tickets {
+			result.Brands[ticket.ID] = ticket.BrandID
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, result, cache.DefaultConfig().DefaultTTL)
+
+	return ds.csatResponse(ctx, result, breakdown, query)
+}
+
+// csatResponse converts ratings to frames with the breakdown series named
+// after their group, assignee or brand
+func (ds *Datasource) csatResponse(ctx context.Context, result *csatResult, breakdown string, query backend.DataQuery) *backend.DataResponse {
+	names, err := ds.csatBreakdownNames(ctx, result, breakdown)
+	if err != nil {
+		if fatal := lookupError(ctx, err); fatal != nil {
+			return &backend.DataResponse{
+				Error: fatal,
+			}
+		}
+	}
+	resp := ds.csatToDataFrames(result, breakdown, names, query)
+	if err != nil {
+		resp.Frames[0].AppendNotices(data.Notice{Severity: data.NoticeSeverityWarning, Text: lookupNotice})
+	}
+	return resp
+}
+
+// csatBreakdownNames returns the names of the breakdown values of ratings.
+// Names left empty are reported by err.
+func (ds *Datasource) csatBreakdownNames(ctx context.Context, result *csatResult, breakdown string) (map[int64]string, error) {
+	var ids []int64
+	for _, rating := range result.Ratings {
+		var id *int64
+		switch breakdown {
+		case "group":
+			id = rating.GroupID
+		case "assignee":
+			id = rating.AssigneeID
+		case "brand":
+			id = result.Brands[rating.TicketID]
+		}
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	switch breakdown {
+	case "group":
+		return ds.groupNamesByID(ctx, ids, nil)
+	case "assignee":
+		return ds.userNames(ctx, ids, nil)
+	default:
+		return ds.brandNames(ctx, ids)
+	}
+}
+
+// brandNames returns the names of the brands ids, from the name cache or
+// listing every brand when one is missing. Names left empty are reported by err.
+func (ds *Datasource) brandNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	names := map[int64]string{}
+	if len(ds.cachedNames("brand", ids, names)) == 0 {
+		return names, nil
+	}
+
+	brands, err := ds.zendeskClient.ListBrands(ctx, maxQueryRows)
+	if err != nil {
+		return names, fmt.Errorf("failed to fetch brand names: %w", err)
+	}
+	fetched := make(map[int64]string, len(brands))
+	for _, brand := range brands {
+		fetched[brand.ID] = brand.Name
+		names[brand.ID] = brand.Name
+	}
+	ds.cacheNames("brand", fetched)
+	return names, nil
+}
+
+// csatBreakdownValue returns the breakdown series a rating belongs to
+func csatBreakdownValue(rating zendesk.SatisfactionRating, breakdown string, brands map[int64]*int64) string {
+	var id *int64
+	switch breakdown {
+	case "group":
+		id = rating.GroupID
+	case "assignee":
+		id = rating.AssigneeID
+	case "brand":
+		id = brands[rating.TicketID]
+	default:
+		return ""
+	}
+	if id == nil {
+		return "none"
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+// csatToDataFrames converts satisfaction ratings to a table frame and
+// good/bad/offered time series bucketed by the query interval, one frame per
+// breakdown value labelled with its id and, when known, its name
+func (ds *Datasource) csatToDataFrames(result *csatResult, breakdown string, names map[int64]string, query backend.DataQuery) *backend.DataResponse {
+	table := data.NewFrame("csat_ratings")
+	table.Fields = append(table.Fields,
+		data.NewField("id", nil, []int64{}),
+		data.NewField("ticket_id", nil, []int64{}),
+		data.NewField("created_at", nil, []*time.Time{}),
+		data.NewField("score", nil, []string{}),
+		data.NewField("comment", nil, []*string{}),
+		data.NewField("reason", nil, []*string{}),
+		data.NewField("assignee_id", nil, []*int64{}),
+		data.NewField("group_id", nil, []*int64{}),
+		data.NewField("requester_id", nil, []int64{}),
+	)
+
+	interval := query.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	sources := map[string]*data.Frame{}
+
+	for _, rating := range result.Ratings {
+		var createdAt *time.Time
+		if t, err := rating.CreatedTime(); err == nil {
+			createdAt = &t
+		}
+		table.AppendRow(
+			rating.ID,
+			rating.TicketID,
+			createdAt,
+			rating.Score,
+			rating.Comment,
+			rating.Reason,
+			rating.AssigneeID,
+			rating.GroupID,
+			rating.RequesterID,
+		)
+
+		// Series are made for the breakdown values rated in the range
+		if createdAt == nil || !rating.Offered() {
+			continue
+		}
+		if createdAt.Before(query.TimeRange.From) || createdAt.After(query.TimeRange.To) {
+			continue
+		}
+		key := csatBreakdownValue(rating, breakdown, result.Brands)
+		if sources[key] == nil {
+			sources[key] = csatSourceFrame()
+		}
+		var good, bad float64
+		switch rating.Score {
+		case zendesk.SatisfactionScoreGood:
+			good = 1
+		case zendesk.SatisfactionScoreBad:
+			bad = 1
+		}
+		sources[key].AppendRow(*createdAt, good, bad, 1.0)
+	}
+
+	keys := make([]string, 0, len(sources))
+	for key := range sources {
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		keys = append(keys, "")
+		sources[""] = csatSourceFrame()
+	}
+	sort.Strings(keys)
+
+	frames := data.Frames{table}
+	for _, key := range keys {
+		var labels data.Labels
+		if breakdown != "" {
+			labels = data.Labels{csatBreakdownLabels[breakdown]: key}
+			if id, err := strconv.ParseInt(key, 10, 64); err == nil && names[id] != "" {
+				labels[csatNameLabels[breakdown]] = names[id]
+			}
+		}
+		series := data.NewFrame("csat")
+		for _, name := range []string{"good", "bad", "offered"} {
+			times, counts, err := bucketSums(sources[key], "created_at", name, query.TimeRange, interval)
+			if err != nil {
+				return &backend.DataResponse{
+					Error:  err,
+					Status: backend.StatusBadRequest,
+				}
+			}
+			if len(series.Fields) == 0 {
+				series.Fields = append(series.Fields, data.NewField("time", nil, times))
+			}
+			series.Fields = append(series.Fields, data.NewField(name, labels, counts))
+		}
+		frames = append(frames, series)
+	}
+
+	return &backend.DataResponse{
+		Frames: frames,
+	}
+}
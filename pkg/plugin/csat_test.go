@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const csatRatings = `{"satisfaction_ratings":[
+	{"id":1,"ticket_id":10,"group_id":5,"score":"good","created_at":"2024-01-01T08:10:00Z"},
+	{"id":2,"ticket_id":11,"group_id":5,"score":"bad","comment":"Slow","created_at":"2024-01-01T08:20:00Z"},
+	{"id":3,"ticket_id":12,"score":"offered","created_at":"2024-01-01T09:30:00Z"},
+	{"id":4,"ticket_id":13,"score":"unoffered","created_at":"2024-01-01T09:40:00Z"}
+],"meta":{"has_more":false}}`
+
+func csatQuery(model string) backend.DataQuery {
+	from := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	return backend.DataQuery{
+		JSON:      []byte(model),
+		Interval:  time.Hour,
+		TimeRange: backend.TimeRange{From: from, To: from.Add(2 * time.Hour)},
+	}
+}
+
+func TestQueryCSAT(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/satisfaction_ratings.json", r.URL.Path)
+		assert.Equal(t, "1704096000", r.URL.Query().Get("start_time"))
+		fmt.Fprint(w, csatRatings)
+	}))
+
+	resp := ds.handleQuery(context.Background(), csatQuery(`{"queryType":"csat"}`))
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 2)
+	assert.Equal(t, 4, resp.Frames[0].Rows())
+
+	series := resp.Frames[1]
+	require.Equal(t, 3, series.Rows())
+	good, _ := series.FieldByName("good")
+	bad, _ := series.FieldByName("bad")
+	offered, _ := series.FieldByName("offered")
+	assert.Equal(t, []int64{1, 0, 0}, []int64{good.At(0).(int64), good.At(1).(int64), good.At(2).(int64)})
+	assert.Equal(t, int64(1), bad.At(0))
+	assert.Equal(t, int64(2), offered.At(0))
+	// Unoffered ratings are not counted
+	assert.Equal(t, int64(1), offered.At(1))
+}
+
+func TestQueryCSAT_BreakdownByBrand(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/satisfaction_ratings.json":
+			fmt.Fprint(w, csatRatings)
+		case "/api/v2/tickets/show_many.json":
+			assert.Equal(t, "10,11,12,13", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"tickets":[{"id":10,"brand_id":1},{"id":11,"brand_id":2},{"id":12,"brand_id":2}]}`)
+		case "/api/v2/brands.json":
+			fmt.Fprint(w, `{"brands":[{"id":1,"name":"Acme"},{"id":2,"name":"Globex"}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), csatQuery(`{"queryType":"csat","breakdown":"brand"}`))
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 3)
+
+	offered, _ := resp.Frames[2].FieldByName("offered")
+	assert.Equal(t, "2", offered.Labels["brand_id"])
+	assert.Equal(t, "Globex", offered.Labels["brand_name"])
+	assert.Equal(t, int64(1), offered.At(0))
+	assert.Equal(t, int64(1), offered.At(1))
+}
+
+func TestQueryCSAT_BreakdownByGroup(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/satisfaction_ratings.json":
+			fmt.Fprint(w, csatRatings)
+		case "/api/v2/groups.json":
+			fmt.Fprint(w, `{"groups":[{"id":5,"name":"Billing"}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), csatQuery(`{"queryType":"csat","breakdown":"group"}`))
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 3)
+
+	good, _ := resp.Frames[1].FieldByName("good")
+	assert.Equal(t, data.Labels{"group_id": "5", "group_name": "Billing"}, good.Labels)
+	// Ratings without a group have no name
+	good, _ = resp.Frames[2].FieldByName("good")
+	assert.Equal(t, data.Labels{"group_id": "none"}, good.Labels)
+}
+
+func TestQueryCSAT_InvalidOptions(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL.Path)
+	}))
+
+	for _, model := range []string{
+		`{"queryType":"csat","breakdown":"tag"}`,
+		`{"queryType":"csat","score":"great"}`,
+	} {
+		resp := ds.handleQuery(context.Background(), csatQuery(model))
+		assert.Error(t, resp.Error, model)
+		assert.Equal(t, backend.StatusBadRequest, resp.Status, model)
+	}
+}
+
+func TestQueryCSAT_TooManyBuckets(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, csatRatings)
+	}))
+
+	query := csatQuery(`{"queryType":"csat"}`)
+	query.Interval = time.Millisecond
+	resp := ds.handleQuery(context.Background(), query)
+	assert.EqualError(t, resp.Error, "interval 1ms is too small for the time range")
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
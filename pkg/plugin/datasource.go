@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
@@ -13,6 +16,17 @@ import (
 	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
 )
 
+// recordColumns are the columns of the ticket, user and organization frames,
+// custom ticket field columns aside
+var recordColumns = map[string][]string{
+	"tickets":       {"id", "subject", "status", "priority", "requester_id", "requester_name", "assignee_id", "assignee_name", "organization_id", "organization_name", "group_id", "group_name", "type", "via_channel", "brand_id", "ticket_form_id", "satisfaction_score", "tags", "created_at", "updated_at", "solved_at", "due_at"},
+	"users":         {"id", "name", "email", "role", "active", "suspended", "organization_id", "time_zone", "tags", "created_at", "last_login_at"},
+	"organizations": {"id", "name", "domain_names", "tags", "created_at", "updated_at"},
+}
+
+// maxQueryRows caps the number of records a single query reads across pages
+const maxQueryRows = 10000
+
 // Datasource represents the Zendesk datasource
 type Datasource struct {
 	zendeskClient *zendesk.Client
@@ -29,16 +43,26 @@ func NewDatasource(ctx context.Context, settings backend.DataSourceInstanceSetti
 
 	var secureConfig SecureConfig
 	if settings.DecryptedSecureJSONData != nil {
-		if apiToken, ok := settings.DecryptedSecureJSONData["apiToken"]; ok {
-			secureConfig.APIToken = apiToken
-		}
+		secureConfig.APIToken = settings.DecryptedSecureJSONData["apiToken"]
+		secureConfig.OAuthAccessToken = settings.DecryptedSecureJSONData["oauthAccessToken"]
+		secureConfig.OAuthClientSecret = settings.DecryptedSecureJSONData["oauthClientSecret"]
 	}
 
-	if config.Subdomain == "" || secureConfig.APIToken == "" {
-		return nil, fmt.Errorf("subdomain and API token are required")
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
-	client := zendesk.NewClient(config.Subdomain, config.Email, secureConfig.APIToken)
+	auth, err := config.Authenticator(secureConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOpts, err := config.ClientOptions(ctx, settings, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	client := zendesk.NewClient(config.Subdomain, config.Email, secureConfig.APIToken, clientOpts...)
 	cacheMgr := cache.NewManager(cache.DefaultConfig().DefaultTTL, cache.DefaultConfig().CleanupInterval)
 
 	return &Datasource{
@@ -60,85 +84,231 @@ func (ds *Datasource) QueryData(ctx context.Context, req *backend.QueryDataReque
 	return response, nil
 }
 
-// handleQuery processes a single query
+// handleQuery processes a single query, classifying Zendesk errors into
+// backend statuses with user-facing messages
 func (ds *Datasource) handleQuery(ctx context.Context, query backend.DataQuery) *backend.DataResponse {
-	var queryModel map[string]interface{}
-	if err := json.Unmarshal(query.JSON, &queryModel); err != nil {
+	resp := ds.runQuery(ctx, query)
+	if resp.Error != nil && resp.Status == 0 {
+		classifyQueryError(resp)
+	}
+	return resp
+}
+
+// runQuery dispatches a single query to its query type
+func (ds *Datasource) runQuery(ctx context.Context, query backend.DataQuery) *backend.DataResponse {
+	model, err := parseQueryModel(query.JSON)
+	if err != nil {
 		return &backend.DataResponse{
-			Error: fmt.Errorf("failed to unmarshal query: %v", err),
+			Error:  err,
+			Status: backend.StatusBadRequest,
 		}
 	}
 
-	queryType, ok := queryModel["queryType"].(string)
-	if !ok {
-		return &backend.DataResponse{
-			Error: fmt.Errorf("queryType is required"),
+	withGroupByCustomField(model)
+	withFilterCustomFields(model)
+	if resp := ds.checkFilterCustomFields(ctx, model); resp != nil {
+		return resp
+	}
+	resp := ds.queryByType(ctx, query, model)
+	if resp.Error != nil || !recordQueryTypes[model.QueryType] {
+		return resp
+	}
+
+	// Advanced filters are evaluated on the records, including those already
+	// pushed down to search
+	if len(model.AdvancedFilters) > 0 {
+		if resp = filterResponse(model, resp); resp.Error != nil {
+			return resp
 		}
 	}
 
-	switch queryType {
+	// Record queries can be bucketed over time or grouped by a dimension
+	if model.Format == formatTimeSeries {
+		return timeSeriesResponse(query, model, resp)
+	}
+	if model.GroupBy != "" {
+		return ds.groupByResponse(ctx, model, resp)
+	}
+	return tableResponse(model, resp)
+}
+
+// queryByType runs a query of the given query type
+func (ds *Datasource) queryByType(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	switch model.QueryType {
 	case "tickets":
-		return ds.queryTickets(ctx, query, queryModel)
+		return ds.queryTickets(ctx, query, model)
 	case "users":
-		return ds.queryUsers(ctx, query, queryModel)
+		return ds.queryUsers(ctx, query, model)
 	case "organizations":
-		return ds.queryOrganizations(ctx, query, queryModel)
+		return ds.queryOrganizations(ctx, query, model)
+	case "search":
+		return ds.querySearch(ctx, query, model)
+	case "ticketById":
+		return ds.queryTicketByID(ctx, query, model)
+	case "ticketMetrics":
+		return ds.queryTicketMetrics(ctx, query, model)
+	case "slaStatus":
+		return ds.querySLAStatus(ctx, query, model)
+	case "statusDurations":
+		return ds.queryStatusDurations(ctx, query, model)
+	case "groups":
+		return ds.queryGroups(ctx, query, model)
+	case "view":
+		return ds.queryView(ctx, query, model)
+	case "csat":
+		return ds.queryCSAT(ctx, query, model)
+	case "stats":
+		return ds.queryStats(ctx, query, model)
+	case "userStats":
+		return ds.queryUserStats(ctx, query, model)
+	case "orgStats":
+		return ds.queryOrgStats(ctx, query, model)
+	case "incrementalTickets":
+		return ds.queryIncrementalTickets(ctx, query, model)
+	case "incrementalUsers":
+		return ds.queryIncrementalUsers(ctx, query, model)
+	case "incrementalOrganizations":
+		return ds.queryIncrementalOrganizations(ctx, query, model)
 	default:
 		return &backend.DataResponse{
-			Error: fmt.Errorf("unknown query type: %s", queryType),
+			Error:  fmt.Errorf("unknown query type: %s", model.QueryType),
+			Status: backend.StatusBadRequest,
 		}
 	}
 }
 
-// queryTickets handles ticket queries
-func (ds *Datasource) queryTickets(ctx context.Context, query backend.DataQuery, queryModel map[string]interface{}) *backend.DataResponse {
-	params := make(map[string]string)
-	if status, ok := queryModel["status"].(string); ok && status != "" {
-		params["status"] = status
+// queryTickets handles ticket queries. A dashboard time range, an assignee
+// or requester and the advanced filters search can apply are pushed down to
+// Zendesk search, as the ticket list cannot be filtered by them.
+func (ds *Datasource) queryTickets(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt, timeFieldSolvedAt)
+	if err != nil {
+		return timeFilterError(err)
 	}
-	if priority, ok := queryModel["priority"].(string); ok && priority != "" {
-		params["priority"] = priority
+
+	params := ticketListParams(model)
+	if terms := filterSearchTerms(model, zendesk.SearchTypeTicket); terms != "" {
+		params["query"] = terms
 	}
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("tickets:%v", params)
+	cacheKey := fmt.Sprintf("tickets:%v:%s", params, filter.key())
 	if cached, found := ds.cacheManager.Get(cacheKey); found {
-		if tickets, ok := cached.(*zendesk.TicketsResponse); ok {
-			return ds.ticketsToDataFrame(tickets)
+		if result, ok := cached.(*ticketsResult); ok {
+			return ds.ticketsResponse(ctx, result.Tickets, result.Sideloads, model)
 		}
 	}
 
 	// Fetch from API
-	tickets, err := ds.zendeskClient.GetTickets(params)
+	result, err := ds.fetchTickets(ctx, params, filter)
 	if err != nil {
 		return &backend.DataResponse{
-			Error: fmt.Errorf("failed to fetch tickets: %v", err),
+			Error: fmt.Errorf("failed to fetch tickets: %w", err),
 		}
 	}
 
 	// Cache the result
-	ds.cacheManager.Set(cacheKey, tickets, cache.DefaultConfig().DefaultTTL)
+	ds.cacheManager.Set(cacheKey, result, cache.DefaultConfig().DefaultTTL)
+
+	return ds.ticketsResponse(ctx, result.Tickets, result.Sideloads, model)
+}
+
+// ticketListParams returns the ticket list params of the status, priority,
+// assignee and requester query options
+func ticketListParams(model *QueryModel) map[string]string {
+	params := make(map[string]string)
+	if model.Status != "" {
+		params["status"] = model.Status
+	}
+	if model.Priority != "" {
+		params["priority"] = model.Priority
+	}
+	if model.AssigneeID > 0 {
+		params["assignee"] = strconv.FormatInt(model.AssigneeID, 10)
+	}
+	if model.RequesterID > 0 {
+		params["requester"] = strconv.FormatInt(model.RequesterID, 10)
+	}
+	return params
+}
+
+// needsTicketSearch reports whether tickets matching params and filter must
+// be searched for, as the ticket list filters neither by time, by user nor
+// by the search terms of advanced filters in the "query" param
+func needsTicketSearch(params map[string]string, filter *timeFilter) bool {
+	_, assignee := params["assignee"]
+	_, requester := params["requester"]
+	_, query := params["query"]
+	return filter.active() || assignee || requester || query
+}
+
+// fetchTickets lists tickets matching params, searching for them when
+// needsTicketSearch. Searches read through the export API return no
+// side-loads; ticketLookups then fetches the names in bulk and caches them.
+func (ds *Datasource) fetchTickets(ctx context.Context, params map[string]string, filter *timeFilter) (*ticketsResult, error) {
+	if !needsTicketSearch(params, filter) {
+		tickets, sideloads, err := ds.zendeskClient.ListTicketsWithSideloads(ctx, params, ticketIncludes, maxQueryRows)
+		if err != nil {
+			return nil, err
+		}
+		return &ticketsResult{Tickets: tickets, Sideloads: sideloads}, nil
+	}
 
-	return ds.ticketsToDataFrame(tickets)
+	results, err := ds.zendeskClient.Search(ctx, ticketSearchQuery(params, filter), zendesk.SearchTypeTicket, maxQueryRows, ticketIncludes...)
+	if err != nil {
+		return nil, err
+	}
+	return &ticketsResult{Tickets: filter.tickets(results.Tickets), Sideloads: &results.Sideloads}, nil
 }
 
-// queryUsers handles user queries
-func (ds *Datasource) queryUsers(ctx context.Context, query backend.DataQuery, queryModel map[string]interface{}) *backend.DataResponse {
+// ticketSearchQuery translates ticket list params and a time range to a
+// Zendesk search query
+func ticketSearchQuery(params map[string]string, filter *timeFilter) string {
+	var terms []string
+	for _, key := range []string{"status", "priority", "assignee", "requester"} {
+		if value, ok := params[key]; ok {
+			terms = append(terms, fmt.Sprintf("%s:%s", key, value))
+		}
+	}
+	if query, ok := params["query"]; ok {
+		terms = append(terms, query)
+	}
+	return filter.withSearchTerms(strings.Join(terms, " "))
+}
+
+// queryUsers handles user queries. A dashboard time range and the advanced
+// filters search can apply are pushed down to Zendesk search.
+func (ds *Datasource) queryUsers(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+
 	params := make(map[string]string)
+	terms := filterSearchTerms(model, zendesk.SearchTypeUser)
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("users:%v", params)
+	cacheKey := fmt.Sprintf("users:%v:%s:%s", params, terms, filter.key())
 	if cached, found := ds.cacheManager.Get(cacheKey); found {
-		if users, ok := cached.(*zendesk.UsersResponse); ok {
+		if users, ok := cached.([]zendesk.User); ok {
 			return ds.usersToDataFrame(users)
 		}
 	}
 
 	// Fetch from API
-	users, err := ds.zendeskClient.GetUsers(params)
+	var users []zendesk.User
+	if filter.active() || terms != "" {
+		var results *zendesk.SearchResults
+		results, err = ds.zendeskClient.Search(ctx, filter.withSearchTerms(terms), zendesk.SearchTypeUser, maxQueryRows)
+		if err == nil {
+			users = filter.users(results.Users)
+		}
+	} else {
+		users, err = ds.zendeskClient.ListUsers(ctx, params, maxQueryRows)
+	}
 	if err != nil {
 		return &backend.DataResponse{
-			Error: fmt.Errorf("failed to fetch users: %v", err),
+			Error: fmt.Errorf("failed to fetch users: %w", err),
 		}
 	}
 
@@ -148,23 +318,39 @@ func (ds *Datasource) queryUsers(ctx context.Context, query backend.DataQuery, q
 	return ds.usersToDataFrame(users)
 }
 
-// queryOrganizations handles organization queries
-func (ds *Datasource) queryOrganizations(ctx context.Context, query backend.DataQuery, queryModel map[string]interface{}) *backend.DataResponse {
+// queryOrganizations handles organization queries. A dashboard time range
+// and the advanced filters search can apply are pushed down to Zendesk search.
+func (ds *Datasource) queryOrganizations(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+
 	params := make(map[string]string)
+	terms := filterSearchTerms(model, zendesk.SearchTypeOrganization)
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("organizations:%v", params)
+	cacheKey := fmt.Sprintf("organizations:%v:%s:%s", params, terms, filter.key())
 	if cached, found := ds.cacheManager.Get(cacheKey); found {
-		if orgs, ok := cached.(*zendesk.OrganizationsResponse); ok {
+		if orgs, ok := cached.([]zendesk.Organization); ok {
 			return ds.organizationsToDataFrame(orgs)
 		}
 	}
 
 	// Fetch from API
-	orgs, err := ds.zendeskClient.GetOrganizations(params)
+	var orgs []zendesk.Organization
+	if filter.active() || terms != "" {
+		var results *zendesk.SearchResults
+		results, err = ds.zendeskClient.Search(ctx, filter.withSearchTerms(terms), zendesk.SearchTypeOrganization, maxQueryRows)
+		if err == nil {
+			orgs = filter.organizations(results.Organizations)
+		}
+	} else {
+		orgs, err = ds.zendeskClient.ListOrganizations(ctx, params, maxQueryRows)
+	}
 	if err != nil {
 		return &backend.DataResponse{
-			Error: fmt.Errorf("failed to fetch organizations: %v", err),
+			Error: fmt.Errorf("failed to fetch organizations: %w", err),
 		}
 	}
 
@@ -174,18 +360,40 @@ func (ds *Datasource) queryOrganizations(ctx context.Context, query backend.Data
 	return ds.organizationsToDataFrame(orgs)
 }
 
-// ticketsToDataFrame converts tickets to Grafana DataFrame
-func (ds *Datasource) ticketsToDataFrame(tickets *zendesk.TicketsResponse) *backend.DataResponse {
+// ticketsToDataFrame converts tickets to Grafana DataFrame, resolving user,
+// group and organization names and adding a column per custom field of lookups
+func (ds *Datasource) ticketsToDataFrame(tickets []zendesk.Ticket, lookups *ticketLookups) *backend.DataResponse {
+	if lookups == nil {
+		lookups = &ticketLookups{}
+	}
+
 	frame := data.NewFrame("tickets")
 	frame.Fields = append(frame.Fields,
 		data.NewField("id", nil, []int64{}),
 		data.NewField("subject", nil, []string{}),
 		data.NewField("status", nil, []string{}),
 		data.NewField("priority", nil, []string{}),
-		data.NewField("created_at", nil, []string{}),
+		data.NewField("requester_id", nil, []int64{}),
+		data.NewField("requester_name", nil, []string{}),
+		data.NewField("assignee_id", nil, []*int64{}),
+		data.NewField("assignee_name", nil, []string{}),
+		data.NewField("organization_id", nil, []*int64{}),
+		data.NewField("organization_name", nil, []string{}),
+		data.NewField("group_id", nil, []*int64{}),
+		data.NewField("group_name", nil, []string{}),
+		data.NewField("type", nil, []string{}),
+		data.NewField("via_channel", nil, []string{}),
+		data.NewField("brand_id", nil, []*int64{}),
+		data.NewField("ticket_form_id", nil, []*int64{}),
+		data.NewField("satisfaction_score", nil, []string{}),
+		data.NewField("tags", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("updated_at", nil, []time.Time{}),
+		data.NewField("solved_at", nil, []*time.Time{}),
+		data.NewField("due_at", nil, []*time.Time{}),
 	)
 
-	for _, ticket := range tickets.Tickets {
+	for _, ticket := range tickets {
 		subject := ""
 		if ticket.Subject != nil {
 			subject = *ticket.Subject
@@ -194,15 +402,60 @@ func (ds *Datasource) ticketsToDataFrame(tickets *zendesk.TicketsResponse) *back
 		if ticket.Priority != nil {
 			priority = *ticket.Priority
 		}
+		assigneeName := ""
+		if ticket.AssigneeID != nil {
+			assigneeName = lookups.Users[*ticket.AssigneeID]
+		}
+		organizationName := ""
+		if ticket.OrganizationID != nil {
+			organizationName = lookups.Organizations[*ticket.OrganizationID]
+		}
+		groupName := ""
+		if ticket.GroupID != nil {
+			groupName = lookups.Groups[*ticket.GroupID]
+		}
+		ticketType := ""
+		if ticket.Type != nil {
+			ticketType = *ticket.Type
+		}
+		channel := ""
+		if ticket.Via != nil {
+			channel = ticket.Via.Channel
+		}
+		satisfaction := ""
+		if ticket.SatisfactionRating != nil {
+			satisfaction = ticket.SatisfactionRating.Score
+		}
 
 		frame.AppendRow(
 			ticket.ID,
 			subject,
 			ticket.Status,
 			priority,
+			ticket.RequesterID,
+			lookups.Users[ticket.RequesterID],
+			ticket.AssigneeID,
+			assigneeName,
+			ticket.OrganizationID,
+			organizationName,
+			ticket.GroupID,
+			groupName,
+			ticketType,
+			channel,
+			ticket.BrandID,
+			ticket.TicketFormID,
+			satisfaction,
+			strings.Join(ticket.Tags, " "),
 			ticket.CreatedAt,
+			ticket.UpdatedAt,
+			lookups.SolvedAt[ticket.ID],
+			ticket.DueAt,
 		)
 	}
+	frame.Fields = append(frame.Fields, customFieldColumns(tickets, lookups.CustomFields)...)
+	if lookups.Incomplete {
+		frame.AppendNotices(data.Notice{Severity: data.NoticeSeverityWarning, Text: lookupNotice})
+	}
 
 	return &backend.DataResponse{
 		Frames: data.Frames{frame},
@@ -210,7 +463,7 @@ func (ds *Datasource) ticketsToDataFrame(tickets *zendesk.TicketsResponse) *back
 }
 
 // usersToDataFrame converts users to Grafana DataFrame
-func (ds *Datasource) usersToDataFrame(users *zendesk.UsersResponse) *backend.DataResponse {
+func (ds *Datasource) usersToDataFrame(users []zendesk.User) *backend.DataResponse {
 	frame := data.NewFrame("users")
 	frame.Fields = append(frame.Fields,
 		data.NewField("id", nil, []int64{}),
@@ -218,15 +471,27 @@ func (ds *Datasource) usersToDataFrame(users *zendesk.UsersResponse) *backend.Da
 		data.NewField("email", nil, []string{}),
 		data.NewField("role", nil, []string{}),
 		data.NewField("active", nil, []bool{}),
+		data.NewField("suspended", nil, []bool{}),
+		data.NewField("organization_id", nil, []*int64{}),
+		data.NewField("time_zone", nil, []string{}),
+		data.NewField("tags", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("last_login_at", nil, []*time.Time{}),
 	)
 
-	for _, user := range users.Users {
+	for _, user := range users {
 		frame.AppendRow(
 			user.ID,
 			user.Name,
 			user.Email,
 			user.Role,
 			user.Active,
+			user.Suspended,
+			user.OrganizationID,
+			user.TimeZone,
+			strings.Join(user.Tags, " "),
+			user.CreatedAt,
+			user.LastLoginAt,
 		)
 	}
 
@@ -236,16 +501,18 @@ func (ds *Datasource) usersToDataFrame(users *zendesk.UsersResponse) *backend.Da
 }
 
 // organizationsToDataFrame converts organizations to Grafana DataFrame
-func (ds *Datasource) organizationsToDataFrame(orgs *zendesk.OrganizationsResponse) *backend.DataResponse {
+func (ds *Datasource) organizationsToDataFrame(orgs []zendesk.Organization) *backend.DataResponse {
 	frame := data.NewFrame("organizations")
 	frame.Fields = append(frame.Fields,
 		data.NewField("id", nil, []int64{}),
 		data.NewField("name", nil, []string{}),
 		data.NewField("domain_names", nil, []string{}),
-		data.NewField("created_at", nil, []string{}),
+		data.NewField("tags", nil, []string{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("updated_at", nil, []time.Time{}),
 	)
 
-	for _, org := range orgs.Organizations {
+	for _, org := range orgs {
 		domains := ""
 		if len(org.DomainNames) > 0 {
 			domains = org.DomainNames[0] // Simplified
@@ -254,7 +521,9 @@ func (ds *Datasource) organizationsToDataFrame(orgs *zendesk.OrganizationsRespon
 			org.ID,
 			org.Name,
 			domains,
+			strings.Join(org.Tags, " "),
 			org.CreatedAt,
+			org.UpdatedAt,
 		)
 	}
 
@@ -276,6 +545,12 @@ func (ds *Datasource) CallResource(ctx context.Context, req *backend.CallResourc
 		return ds.handleFields(ctx, req, sender)
 	case "health":
 		return ds.handleHealth(ctx, req, sender)
+	case "groups":
+		return ds.handleGroups(ctx, req, sender)
+	case "ticket-fields":
+		return ds.handleTicketFields(ctx, req, sender)
+	case "views":
+		return ds.handleViews(ctx, req, sender)
 	default:
 		return sender.Send(&backend.CallResourceResponse{
 			Status: 404,
@@ -315,7 +590,7 @@ func (ds *Datasource) handleExport(ctx context.Context, req *backend.CallResourc
 
 	switch queryType {
 	case "tickets":
-		tickets, err := ds.zendeskClient.GetTickets(params)
+		tickets, err := ds.zendeskClient.GetTickets(ctx, params)
 		if err != nil {
 			return sender.Send(&backend.CallResourceResponse{
 				Status: 500,
@@ -324,7 +599,7 @@ func (ds *Datasource) handleExport(ctx context.Context, req *backend.CallResourc
 		}
 		data, contentType, err = ds.ExportTickets(tickets, ExportFormat(format))
 	case "users":
-		users, err := ds.zendeskClient.GetUsers(params)
+		users, err := ds.zendeskClient.GetUsers(ctx, params)
 		if err != nil {
 			return sender.Send(&backend.CallResourceResponse{
 				Status: 500,
@@ -388,13 +663,7 @@ func (ds *Datasource) handleBatchQuery(ctx context.Context, req *backend.CallRes
 
 // handleFields returns available fields
 func (ds *Datasource) handleFields(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
-	fields := map[string][]string{
-		"tickets":      {"id", "subject", "status", "priority", "created_at", "updated_at"},
-		"users":        {"id", "name", "email", "role", "active", "created_at"},
-		"organizations": {"id", "name", "domain_names", "created_at"},
-	}
-
-	response, err := json.Marshal(fields)
+	response, err := json.Marshal(recordColumns)
 	if err != nil {
 		return sender.Send(&backend.CallResourceResponse{
 			Status: 500,
@@ -411,7 +680,7 @@ func (ds *Datasource) handleFields(ctx context.Context, req *backend.CallResourc
 
 // handleHealth handles health check requests
 func (ds *Datasource) handleHealth(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
-	if err := ds.zendeskClient.TestConnection(); err != nil {
+	if err := ds.zendeskClient.TestConnection(ctx); err != nil {
 		return sender.Send(&backend.CallResourceResponse{
 			Status: 503,
 			Body:   []byte(fmt.Sprintf(`{"status":"error","message":"%v"}`, err)),
@@ -425,3 +694,19 @@ func (ds *Datasource) handleHealth(ctx context.Context, req *backend.CallResourc
 	})
 }
 
+// sendJSON sends v as a JSON resource response
+func sendJSON(sender backend.CallResourceResponseSender, v interface{}) error {
+	response, err := json.Marshal(v)
+	if err != nil {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: 500,
+			Body:   []byte(fmt.Sprintf(`{"error":"Failed to marshal response: %v"}`, err)),
+		})
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status:  200,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    response,
+	})
+}
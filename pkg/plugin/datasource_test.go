@@ -3,6 +3,9 @@ package plugin
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -45,3 +48,43 @@ func TestNewDatasource_MissingToken(t *testing.T) {
 	assert.Error(t, err)
 }
 
+
+func TestNewDatasource_OAuthToken(t *testing.T) {
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: json.RawMessage(`{"subdomain":"test","authMode":"oauthToken"}`),
+		DecryptedSecureJSONData: map[string]string{
+			"oauthAccessToken": "test-token",
+		},
+	}
+
+	ds, err := NewDatasource(context.Background(), settings)
+	require.NoError(t, err)
+	assert.NotNil(t, ds)
+}
+
+func TestNewDatasource_OAuthClientCredentialsMissingSecret(t *testing.T) {
+	settings := backend.DataSourceInstanceSettings{
+		JSONData:                json.RawMessage(`{"subdomain":"test","authMode":"oauthClientCredentials","oauthClientId":"grafana"}`),
+		DecryptedSecureJSONData: map[string]string{},
+	}
+
+	_, err := NewDatasource(context.Background(), settings)
+	assert.Error(t, err)
+}
+
+// newTestDatasource returns a datasource whose Zendesk client talks to a test server running handler
+func newTestDatasource(t *testing.T, handler http.Handler) *Datasource {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	settings := backend.DataSourceInstanceSettings{
+		JSONData: json.RawMessage(fmt.Sprintf(`{"baseUrl":"%s/api/v2","retryMaxAttempts":1}`, server.URL)),
+		DecryptedSecureJSONData: map[string]string{
+			"apiToken": "test-token",
+		},
+	}
+	instance, err := NewDatasource(context.Background(), settings)
+	require.NoError(t, err)
+	return instance.(*Datasource)
+}
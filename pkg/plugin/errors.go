@@ -0,0 +1,55 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// classifyQueryError sets the status, error source and a user-facing message
+// of a failed query response from the Zendesk error it carries. Errors that
+// did not come from Zendesk are left as they are.
+func classifyQueryError(resp *backend.DataResponse) {
+	err := resp.Error
+
+	var message string
+	switch {
+	case errors.Is(err, zendesk.ErrUnauthorized):
+		resp.Status = backend.StatusUnauthorized
+		message = "Zendesk rejected the credentials, check the authentication settings of the data source"
+	case errors.Is(err, zendesk.ErrForbidden):
+		resp.Status = backend.StatusForbidden
+		message = "the Zendesk user of the data source is not allowed to read this data"
+	case errors.Is(err, zendesk.ErrNotFound):
+		resp.Status = backend.StatusNotFound
+		message = "the requested Zendesk resource does not exist"
+	case errors.Is(err, zendesk.ErrRateLimited):
+		resp.Status = backend.StatusTooManyRequests
+		message = "Zendesk rate limit reached, retry later"
+		var apiErr *zendesk.APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			message = fmt.Sprintf("Zendesk rate limit reached, retry in %s", apiErr.RetryAfter)
+		}
+	case errors.Is(err, zendesk.ErrServerError):
+		resp.Status = backend.StatusBadGateway
+		message = "Zendesk is temporarily unavailable"
+	case errors.Is(err, context.DeadlineExceeded):
+		resp.Status = backend.StatusTimeout
+		message = "Zendesk did not respond in time"
+	default:
+		var apiErr *zendesk.APIError
+		if !errors.As(err, &apiErr) {
+			return
+		}
+		// Other client errors mean Zendesk rejected the query itself
+		resp.Status = backend.StatusBadRequest
+		message = "Zendesk rejected the query"
+	}
+
+	resp.ErrorSource = backend.ErrorSourceDownstream
+	resp.Error = fmt.Errorf("%s: %w", message, err)
+}
@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+func TestHandleQuery_ClassifiesZendeskErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   backend.Status
+	}{
+		{http.StatusUnauthorized, backend.StatusUnauthorized},
+		{http.StatusForbidden, backend.StatusForbidden},
+		{http.StatusNotFound, backend.StatusNotFound},
+		{http.StatusBadRequest, backend.StatusBadRequest},
+		{http.StatusInternalServerError, backend.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"error":"Failure","description":"Something went wrong"}`)
+			}))
+
+			resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"users"}`)})
+			require.Error(t, resp.Error)
+			assert.Equal(t, tt.want, resp.Status)
+			assert.Equal(t, backend.ErrorSourceDownstream, resp.ErrorSource)
+
+			var apiErr *zendesk.APIError
+			assert.True(t, errors.As(resp.Error, &apiErr))
+		})
+	}
+}
+
+func TestHandleQuery_UnauthorizedMessage(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"Couldn't authenticate you"}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets"}`)})
+	require.Error(t, resp.Error)
+	assert.Contains(t, resp.Error.Error(), "Zendesk rejected the credentials")
+	assert.True(t, errors.Is(resp.Error, zendesk.ErrUnauthorized))
+}
+
+func TestHandleQuery_InvalidQueryIsBadRequest(t *testing.T) {
+	ds := newTestDatasource(t, http.NotFoundHandler())
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"unknown"}`)})
+	require.Error(t, resp.Error)
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
 )
@@ -85,8 +86,8 @@ func (ds *Datasource) ticketsToCSV(tickets *zendesk.TicketsResponse) []byte {
 			subject,
 			ticket.Status,
 			priority,
-			ticket.CreatedAt,
-			ticket.UpdatedAt,
+			formatTime(ticket.CreatedAt),
+			formatTime(ticket.UpdatedAt),
 			fmt.Sprintf("%d", ticket.RequesterID),
 			assigneeID,
 		})
@@ -112,8 +113,8 @@ func (ds *Datasource) usersToCSV(users *zendesk.UsersResponse) []byte {
 			user.Email,
 			user.Role,
 			fmt.Sprintf("%t", user.Active),
-			user.CreatedAt,
-			user.UpdatedAt,
+			formatTime(user.CreatedAt),
+			formatTime(user.UpdatedAt),
 		})
 	}
 
@@ -136,8 +137,8 @@ func (ds *Datasource) organizationsToCSV(orgs *zendesk.OrganizationsResponse) []
 			fmt.Sprintf("%d", org.ID),
 			org.Name,
 			domains,
-			org.CreatedAt,
-			org.UpdatedAt,
+			formatTime(org.CreatedAt),
+			formatTime(org.UpdatedAt),
 		})
 	}
 
@@ -145,3 +146,10 @@ func (ds *Datasource) organizationsToCSV(orgs *zendesk.OrganizationsResponse) []
 	return []byte(b.String())
 }
 
+// formatTime formats a timestamp for CSV export, leaving unset timestamps empty
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
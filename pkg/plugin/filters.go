@@ -0,0 +1,578 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// multiValueColumns hold space separated values; a filter on them matches a
+// record when it matches any of its values
+var multiValueColumns = map[string]bool{
+	"tags": true,
+}
+
+// filterSearchKeywords maps the record columns Zendesk search can filter by
+// to their search keyword, per search result type
+var filterSearchKeywords = map[string]map[string]string{
+	zendesk.SearchTypeTicket: {
+		"status":          "status",
+		"priority":        "priority",
+		"type":            "ticket_type",
+		"tags":            "tags",
+		"assignee_id":     "assignee",
+		"requester_id":    "requester",
+		"group_id":        "group",
+		"organization_id": "organization",
+		"brand_id":        "brand",
+		"via_channel":     "via",
+		"created_at":      "created",
+		"updated_at":      "updated",
+	},
+	zendesk.SearchTypeUser: {
+		"role":            "role",
+		"tags":            "tags",
+		"organization_id": "organization",
+		"created_at":      "created",
+	},
+	zendesk.SearchTypeOrganization: {
+		"tags":       "tags",
+		"created_at": "created",
+		"updated_at": "updated",
+	},
+}
+
+// filterTimeLayouts are the layouts a filter value on a time column is read with
+var filterTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// filterExpr is a compiled list of advanced filters. AND binds tighter than
+// OR, so the filters form groups joined by OR of filters joined by AND.
+type filterExpr struct {
+	groups [][]AdvancedFilter
+}
+
+// compileFilters groups filters by their logic. The logic of the first
+// filter is ignored as nothing precedes it.
+func compileFilters(filters []AdvancedFilter) *filterExpr {
+	expr := &filterExpr{}
+	for i, filter := range filters {
+		if i == 0 || filter.Logic == logicOr {
+			expr.groups = append(expr.groups, nil)
+		}
+		last := len(expr.groups) - 1
+		expr.groups[last] = append(expr.groups[last], filter)
+	}
+	return expr
+}
+
+// filterSearchTerms returns the Zendesk search terms of the advanced filters
+// of a query that search can apply to records of resultType
+func filterSearchTerms(model *QueryModel, resultType string) string {
+	return compileFilters(model.AdvancedFilters).searchTerms(resultType)
+}
+
+// searchTerms returns the filters Zendesk search can apply as search terms.
+// Search has no OR across keywords, so only a single AND group is pushed
+// down. Pushed filters are still evaluated in memory, which keeps the
+// results exact where search matching is looser.
+func (e *filterExpr) searchTerms(resultType string) string {
+	if len(e.groups) != 1 {
+		return ""
+	}
+	keywords := filterSearchKeywords[resultType]
+	var terms []string
+	for _, filter := range e.groups[0] {
+		keyword, ok := keywords[filter.Field]
+		if !ok {
+			continue
+		}
+		if term, ok := searchTerm(keyword, filter); ok {
+			terms = append(terms, term)
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// searchTerm translates a filter to a search term: equality on any keyword
+// and ranges on the created and updated times
+func searchTerm(keyword string, filter AdvancedFilter) (string, bool) {
+	if keyword == "created" || keyword == "updated" {
+		s, ok := filter.Value.(string)
+		if !ok {
+			return "", false
+		}
+		t, ok := parseFilterTime(s)
+		if !ok {
+			return "", false
+		}
+		switch filter.Operator {
+		case operatorGreaterThan:
+			return fmt.Sprintf("%s>%s", keyword, t.UTC().Format(time.RFC3339)), true
+		case operatorLessThan:
+			return fmt.Sprintf("%s<%s", keyword, t.UTC().Format(time.RFC3339)), true
+		}
+		return "", false
+	}
+
+	var value string
+	switch v := filter.Value.(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, `"`) {
+			return "", false
+		}
+		value = v
+		if strings.ContainsAny(v, " :") {
+			value = strconv.Quote(v)
+		}
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return "", false
+	}
+	switch filter.Operator {
+	case operatorEquals:
+		return fmt.Sprintf("%s:%s", keyword, value), true
+	case operatorNotEquals:
+		return fmt.Sprintf("-%s:%s", keyword, value), true
+	}
+	return "", false
+}
+
+// parseFilterTime reads a filter value on a time column
+func parseFilterTime(s string) (time.Time, bool) {
+	for _, layout := range filterTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// filterColumns returns the record columns the advanced filters of a query
+// can refer to, and whether they can refer to custom ticket fields as well.
+// Query types without record frames have none.
+func filterColumns(model *QueryModel) ([]string, bool) {
+	switch model.QueryType {
+	case "tickets", "view", "incrementalTickets":
+		return recordColumns["tickets"], true
+	case "users", "incrementalUsers":
+		return recordColumns["users"], false
+	case "organizations", "incrementalOrganizations":
+		return recordColumns["organizations"], false
+	case "ticketMetrics":
+		return ticketMetricsColumns(model.IncludeTickets), false
+	case "search":
+		switch model.SearchType {
+		case "", zendesk.SearchTypeTicket:
+			return recordColumns["tickets"], true
+		case zendesk.SearchTypeUser:
+			return recordColumns["users"], false
+		case zendesk.SearchTypeOrganization:
+			return recordColumns["organizations"], false
+		}
+
+		// Mixed results have the columns of every result type
+		var columns []string
+		for _, kind := range []string{"tickets", "users", "organizations"} {
+			columns = append(columns, recordColumns[kind]...)
+		}
+		return columns, false
+	}
+	return nil, false
+}
+
+// filterSearchType returns the search result type the advanced filters of a
+// query are pushed down to, or "" when the query does not search
+func filterSearchType(model *QueryModel) string {
+	switch model.QueryType {
+	case "tickets":
+		return zendesk.SearchTypeTicket
+	case "users":
+		return zendesk.SearchTypeUser
+	case "organizations":
+		return zendesk.SearchTypeOrganization
+	case "search":
+		switch model.SearchType {
+		case "":
+			return zendesk.SearchTypeTicket
+		case zendesk.SearchTypeTicket, zendesk.SearchTypeUser, zendesk.SearchTypeOrganization:
+			return model.SearchType
+		}
+	}
+	return ""
+}
+
+// withFilterCustomFields adds the custom ticket fields advanced filters refer
+// to by title to the customFields of a query returning tickets, so ticket
+// frames carry their columns
+func withFilterCustomFields(model *QueryModel) {
+	columns, custom := filterColumns(model)
+	if !custom {
+		return
+	}
+	for _, filter := range model.AdvancedFilters {
+		if !containsString(columns, filter.Field) {
+			withCustomField(model, filter.Field)
+		}
+	}
+}
+
+// checkFilterCustomFields resolves the custom ticket fields advanced filters
+// refer to before any records are fetched, so an unknown or ambiguous field
+// fails the query as a bad request
+func (ds *Datasource) checkFilterCustomFields(ctx context.Context, model *QueryModel) *backend.DataResponse {
+	columns, custom := filterColumns(model)
+	if !custom {
+		return nil
+	}
+	var selection []interface{}
+	for _, filter := range model.AdvancedFilters {
+		if !containsString(columns, filter.Field) {
+			selection = append(selection, filter.Field)
+		}
+	}
+	if len(selection) == 0 {
+		return nil
+	}
+
+	fields, err := ds.listTicketFields(ctx)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch ticket fields: %w", err),
+		}
+	}
+	if _, err := selectCustomFields(fields, selection); err != nil {
+		return &backend.DataResponse{
+			Error:  err,
+			Status: backend.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+// filterCapNotice warns that filters evaluated in memory only saw the
+// records read up to the row cap
+const filterCapNotice = "The records read were capped before advanced filters Zendesk search cannot apply were evaluated, so matching records past the cap are missing."
+
+// filterResponse keeps the records of resp matching the advanced filters of
+// the query. When the fetch was capped and some filters could not be pushed
+// down to search, the first frame notes that matches may be missing.
+func filterResponse(model *QueryModel, resp *backend.DataResponse) *backend.DataResponse {
+	expr := compileFilters(model.AdvancedFilters)
+	capped := recordsCapped(model, resp.Frames)
+	frames, err := expr.filterFrames(resp.Frames)
+	if err != nil {
+		return &backend.DataResponse{
+			Error:  err,
+			Status: backend.StatusBadRequest,
+		}
+	}
+	if capped && expr.memoryOnly(filterSearchType(model)) && len(frames) > 0 {
+		frames[0].AppendNotices(data.Notice{
+			Severity: data.NoticeSeverityWarning,
+			Text:     filterCapNotice,
+		})
+	}
+	return &backend.DataResponse{
+		Frames: frames,
+	}
+}
+
+// recordsCapped reports whether the record frames of a query reached the row
+// cap of its fetch; searches of mixed results stop at the search limit
+func recordsCapped(model *QueryModel, frames data.Frames) bool {
+	limit := maxQueryRows
+	if model.QueryType == "search" && filterSearchType(model) == "" {
+		limit = zendesk.MaxSearchResults
+	}
+	rows := 0
+	for _, frame := range frames {
+		rows += frame.Rows()
+	}
+	return rows >= limit
+}
+
+// memoryOnly reports whether some filters are not pushed down to a search of
+// records of resultType, so they are only evaluated on the records read
+func (e *filterExpr) memoryOnly(resultType string) bool {
+	if len(e.groups) != 1 {
+		return true
+	}
+	keywords := filterSearchKeywords[resultType]
+	for _, filter := range e.groups[0] {
+		keyword, ok := keywords[filter.Field]
+		if !ok {
+			return true
+		}
+		if _, ok := searchTerm(keyword, filter); !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// rowPredicate reports whether row i of a frame matches a filter
+type rowPredicate func(i int) bool
+
+// filterFrames keeps the rows of each frame matching the filters. A filter
+// field must be a column of some frame; in frames without it, it reads as null.
+func (e *filterExpr) filterFrames(frames data.Frames) (data.Frames, error) {
+	for _, group := range e.groups {
+		for _, filter := range group {
+			found := false
+			for _, frame := range frames {
+				if field, _ := frame.FieldByName(filter.Field); field != nil {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unknown filter field: %s", filter.Field)
+			}
+		}
+	}
+
+	filtered := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		groups := make([][]rowPredicate, len(e.groups))
+		for g, group := range e.groups {
+			for _, filter := range group {
+				field, _ := frame.FieldByName(filter.Field)
+				predicate, err := compileFilter(field, filter)
+				if err != nil {
+					return nil, err
+				}
+				groups[g] = append(groups[g], predicate)
+			}
+		}
+
+		kept := frame.EmptyCopy()
+		kept.Meta = frame.Meta
+		for i := 0; i < frame.Rows(); i++ {
+			if matchesAnyGroup(groups, i) {
+				kept.AppendRow(frame.RowCopy(i)...)
+			}
+		}
+		filtered = append(filtered, kept)
+	}
+	return filtered, nil
+}
+
+// matchesAnyGroup reports whether row i matches every predicate of some group
+func matchesAnyGroup(groups [][]rowPredicate, i int) bool {
+	for _, group := range groups {
+		matched := true
+		for _, predicate := range group {
+			if !predicate(i) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// compileFilter builds the predicate of a filter on a frame column, reading
+// the filter value as the column type. Null values only match not_equals.
+func compileFilter(field *data.Field, filter AdvancedFilter) (rowPredicate, error) {
+	if field == nil {
+		return func(int) bool { return filter.Operator == operatorNotEquals }, nil
+	}
+
+	switch {
+	case field.Type().Numeric():
+		return compileNumberFilter(field, filter)
+	case field.Type().Time():
+		return compileTimeFilter(field, filter)
+	case field.Type() == data.FieldTypeBool || field.Type() == data.FieldTypeNullableBool:
+		return compileBoolFilter(field, filter)
+	}
+
+	target := filterString(filter.Value)
+	multi := multiValueColumns[filter.Field]
+	return func(i int) bool {
+		v, ok := field.ConcreteAt(i)
+		if !ok {
+			return filter.Operator == operatorNotEquals
+		}
+		value := fmt.Sprint(v)
+		if !multi {
+			return matchString(filter.Operator, value, target)
+		}
+
+		// Multi-valued columns match when any value does; not_equals when none equals
+		values := strings.Fields(value)
+		if filter.Operator == operatorNotEquals {
+			for _, value := range values {
+				if matchString(operatorEquals, value, target) {
+					return false
+				}
+			}
+			return true
+		}
+		for _, value := range values {
+			if matchString(filter.Operator, value, target) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// filterString formats a filter value for comparison with a text column
+func filterString(v interface{}) string {
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// matchString compares text case-insensitively, as Zendesk search does
+func matchString(operator, value, target string) bool {
+	value, target = strings.ToLower(value), strings.ToLower(target)
+	switch operator {
+	case operatorEquals:
+		return value == target
+	case operatorNotEquals:
+		return value != target
+	case operatorContains:
+		return strings.Contains(value, target)
+	case operatorStartsWith:
+		return strings.HasPrefix(value, target)
+	case operatorEndsWith:
+		return strings.HasSuffix(value, target)
+	case operatorGreaterThan:
+		return value > target
+	case operatorLessThan:
+		return value < target
+	}
+	return false
+}
+
+// compileNumberFilter compares a numeric column with a number, or its text
+// with the value for contains, starts_with and ends_with
+func compileNumberFilter(field *data.Field, filter AdvancedFilter) (rowPredicate, error) {
+	switch filter.Operator {
+	case operatorContains, operatorStartsWith, operatorEndsWith:
+		target := filterString(filter.Value)
+		return func(i int) bool {
+			v, ok := field.ConcreteAt(i)
+			return ok && matchString(filter.Operator, filterString(toFloat(v)), target)
+		}, nil
+	}
+
+	var target float64
+	switch v := filter.Value.(type) {
+	case float64:
+		target = v
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("filter on %s: value must be a number", filter.Field)
+		}
+		target = n
+	default:
+		return nil, fmt.Errorf("filter on %s: value must be a number", filter.Field)
+	}
+	return func(i int) bool {
+		v, err := field.NullableFloatAt(i)
+		if err != nil || v == nil {
+			return filter.Operator == operatorNotEquals
+		}
+		switch filter.Operator {
+		case operatorEquals:
+			return *v == target
+		case operatorNotEquals:
+			return *v != target
+		case operatorGreaterThan:
+			return *v > target
+		case operatorLessThan:
+			return *v < target
+		}
+		return false
+	}, nil
+}
+
+// toFloat returns a concrete numeric frame value as a float64
+func toFloat(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case float32:
+		return float64(n)
+	}
+	return v
+}
+
+// compileTimeFilter compares a time column with an RFC 3339 time or a date
+func compileTimeFilter(field *data.Field, filter AdvancedFilter) (rowPredicate, error) {
+	s, ok := filter.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("filter on %s: value must be a time", filter.Field)
+	}
+	target, ok := parseFilterTime(s)
+	if !ok {
+		return nil, fmt.Errorf("filter on %s: value must be a time", filter.Field)
+	}
+	switch filter.Operator {
+	case operatorEquals, operatorNotEquals, operatorGreaterThan, operatorLessThan:
+	default:
+		return nil, fmt.Errorf("filter on %s: %s does not apply to times", filter.Field, filter.Operator)
+	}
+	return func(i int) bool {
+		t, ok := recordTimeAt(field, i)
+		if !ok {
+			return filter.Operator == operatorNotEquals
+		}
+		switch filter.Operator {
+		case operatorEquals:
+			return t.Equal(target)
+		case operatorNotEquals:
+			return !t.Equal(target)
+		case operatorGreaterThan:
+			return t.After(target)
+		}
+		return t.Before(target)
+	}, nil
+}
+
+// compileBoolFilter compares a boolean column with true or false
+func compileBoolFilter(field *data.Field, filter AdvancedFilter) (rowPredicate, error) {
+	var target bool
+	switch v := filter.Value.(type) {
+	case bool:
+		target = v
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("filter on %s: value must be true or false", filter.Field)
+		}
+		target = b
+	default:
+		return nil, fmt.Errorf("filter on %s: value must be true or false", filter.Field)
+	}
+	switch filter.Operator {
+	case operatorEquals, operatorNotEquals:
+	default:
+		return nil, fmt.Errorf("filter on %s: %s does not apply to booleans", filter.Field, filter.Operator)
+	}
+	return func(i int) bool {
+		v, ok := field.ConcreteAt(i)
+		if !ok {
+			return filter.Operator == operatorNotEquals
+		}
+		return (v == target) == (filter.Operator == operatorEquals)
+	}, nil
+}
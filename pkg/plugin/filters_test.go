@@ -0,0 +1,205 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+func TestFilterExpr_SearchTerms(t *testing.T) {
+	filters := []AdvancedFilter{
+		{Field: "status", Operator: operatorEquals, Value: "open"},
+		{Field: "tags", Operator: operatorNotEquals, Value: "spam"},
+		{Field: "assignee_id", Operator: operatorEquals, Value: float64(7)},
+		{Field: "created_at", Operator: operatorGreaterThan, Value: "2024-01-01"},
+		{Field: "subject", Operator: operatorContains, Value: "printer"},
+		{Field: "priority", Operator: operatorGreaterThan, Value: "low"},
+	}
+	assert.Equal(t, "status:open -tags:spam assignee:7 created>2024-01-01T00:00:00Z", compileFilters(filters).searchTerms(zendesk.SearchTypeTicket))
+
+	// Users have no status keyword
+	assert.Equal(t, "-tags:spam created>2024-01-01T00:00:00Z", compileFilters(filters).searchTerms(zendesk.SearchTypeUser))
+
+	// Search cannot express OR across keywords
+	filters[1].Logic = logicOr
+	assert.Equal(t, "", compileFilters(filters).searchTerms(zendesk.SearchTypeTicket))
+}
+
+func TestFilterExpr_FilterFrames(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	replies := func(v float64) *float64 { return &v }
+	frame := data.NewFrame("tickets",
+		data.NewField("id", nil, []int64{1, 2, 3, 4}),
+		data.NewField("status", nil, []string{"open", "Open", "solved", "pending"}),
+		data.NewField("tags", nil, []string{"vip billing", "", "vip", "spam"}),
+		data.NewField("replies", nil, []*float64{replies(3), nil, replies(1), replies(8)}),
+		data.NewField("created_at", nil, []time.Time{day, day.Add(48 * time.Hour), day, day.Add(72 * time.Hour)}),
+		data.NewField("is_public", nil, []bool{true, false, true, true}),
+	)
+
+	for _, tc := range []struct {
+		name    string
+		filters []AdvancedFilter
+		ids     []int64
+	}{
+		{"equals ignores case", []AdvancedFilter{{Field: "status", Operator: operatorEquals, Value: "OPEN"}}, []int64{1, 2}},
+		{"tag equals any tag", []AdvancedFilter{{Field: "tags", Operator: operatorEquals, Value: "vip"}}, []int64{1, 3}},
+		{"tag not equals every tag", []AdvancedFilter{{Field: "tags", Operator: operatorNotEquals, Value: "vip"}}, []int64{2, 4}},
+		{"number greater than", []AdvancedFilter{{Field: "replies", Operator: operatorGreaterThan, Value: "2"}}, []int64{1, 4}},
+		{"null only not equals", []AdvancedFilter{{Field: "replies", Operator: operatorNotEquals, Value: float64(1)}}, []int64{1, 2, 4}},
+		{"time less than", []AdvancedFilter{{Field: "created_at", Operator: operatorLessThan, Value: "2024-01-02T00:00:00Z"}}, []int64{1, 3}},
+		{"boolean", []AdvancedFilter{{Field: "is_public", Operator: operatorEquals, Value: false}}, []int64{2}},
+		{"starts and ends with", []AdvancedFilter{
+			{Field: "status", Operator: operatorStartsWith, Value: "p"},
+			{Field: "status", Operator: operatorEndsWith, Value: "ved", Logic: logicOr},
+		}, []int64{3, 4}},
+		{"AND binds tighter than OR", []AdvancedFilter{
+			{Field: "status", Operator: operatorEquals, Value: "solved"},
+			{Field: "tags", Operator: operatorContains, Value: "spa", Logic: logicOr},
+			{Field: "replies", Operator: operatorLessThan, Value: float64(5), Logic: logicAnd},
+		}, []int64{3}},
+	} {
+		frames, err := compileFilters(tc.filters).filterFrames(data.Frames{frame})
+		require.NoError(t, err, tc.name)
+		id, _ := frames[0].FieldByName("id")
+		var ids []int64
+		for i := 0; i < id.Len(); i++ {
+			ids = append(ids, id.At(i).(int64))
+		}
+		assert.Equal(t, tc.ids, ids, tc.name)
+	}
+}
+
+func TestFilterExpr_InvalidFilters(t *testing.T) {
+	frame := data.NewFrame("tickets",
+		data.NewField("replies", nil, []int64{1}),
+		data.NewField("created_at", nil, []time.Time{{}}),
+		data.NewField("is_public", nil, []bool{true}),
+	)
+	for _, tc := range []struct {
+		filter AdvancedFilter
+		err    string
+	}{
+		{AdvancedFilter{Field: "mood", Operator: operatorEquals, Value: "happy"}, "unknown filter field: mood"},
+		{AdvancedFilter{Field: "replies", Operator: operatorGreaterThan, Value: "many"}, "filter on replies: value must be a number"},
+		{AdvancedFilter{Field: "created_at", Operator: operatorEquals, Value: "yesterday"}, "filter on created_at: value must be a time"},
+		{AdvancedFilter{Field: "created_at", Operator: operatorContains, Value: "2024-01-01"}, "filter on created_at: contains does not apply to times"},
+		{AdvancedFilter{Field: "is_public", Operator: operatorGreaterThan, Value: true}, "filter on is_public: greater_than does not apply to booleans"},
+	} {
+		_, err := compileFilters([]AdvancedFilter{tc.filter}).filterFrames(data.Frames{frame})
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+func TestQueryTickets_AdvancedFilters(t *testing.T) {
+	var searches int
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ticket_fields.json":
+			fmt.Fprint(w, `{"ticket_fields":[{"id":10,"type":"text","title":"Region","removable":true,"active":true}],"meta":{"has_more":false}}`)
+		case "/api/v2/search.json":
+			searches++
+			assert.Equal(t, "type:ticket status:open", r.URL.Query().Get("query"))
+			fmt.Fprint(w, `{"results":[
+				{"id":1,"result_type":"ticket","status":"open","tags":["vip"],"custom_fields":[{"id":10,"value":"EMEA"}]},
+				{"id":2,"result_type":"ticket","status":"open","tags":["vip"],"custom_fields":[{"id":10,"value":"APAC"}]},
+				{"id":3,"result_type":"ticket","status":"open","custom_fields":[{"id":10,"value":"EMEA"}]}],
+				"count":3}`)
+		case "/api/v2/users.json":
+			fmt.Fprint(w, `{"users":[{"id":7,"name":"Alice"}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","advancedFilters":[
+		{"field":"status","operator":"equals","value":"open"},
+		{"field":"Region","operator":"equals","value":"emea","logic":"AND"},
+		{"field":"tags","operator":"contains","value":"vi","logic":"AND"}]}`)})
+	require.NoError(t, resp.Error)
+	id, _ := resp.Frames[0].FieldByName("id")
+	require.Equal(t, 1, id.Len())
+	assert.Equal(t, int64(1), id.At(0))
+	assert.Equal(t, 1, searches)
+
+	resp = ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"users","advancedFilters":[{"field":"mood","operator":"equals","value":"happy"}]}`)})
+	assert.EqualError(t, resp.Error, "advancedFilters[0]: unknown filter field: mood")
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
+
+func TestQueryTickets_UnknownFilterCustomField(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The field is checked before any ticket is fetched
+		assert.Equal(t, "/api/v2/ticket_fields.json", r.URL.Path)
+		fmt.Fprint(w, `{"ticket_fields":[{"id":10,"type":"text","title":"Region","removable":true,"active":true}],"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","advancedFilters":[{"field":"Mood","operator":"equals","value":"happy"}]}`)})
+	assert.EqualError(t, resp.Error, "unknown custom field: Mood")
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
+
+func TestQueryIncrementalUsers_FilterCapNotice(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var users []string
+		for id := 1; id <= maxQueryRows+10; id++ {
+			role := "end-user"
+			if id%2 == 0 {
+				role = "agent"
+			}
+			users = append(users, fmt.Sprintf(`{"id":%d,"role":%q,"active":true,"created_at":"2024-01-01T00:10:00Z","updated_at":"2024-01-01T00:20:00Z"}`, id, role))
+		}
+		fmt.Fprintf(w, `{"users":[%s],"end_of_stream":true}`, strings.Join(users, ","))
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"incrementalUsers","advancedFilters":[{"field":"role","operator":"equals","value":"agent"}]}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	assert.Equal(t, maxQueryRows/2, frame.Rows())
+	require.NotNil(t, frame.Meta)
+	require.Len(t, frame.Meta.Notices, 2)
+	assert.Equal(t, incrementalRowCapNotice, frame.Meta.Notices[0].Text)
+	assert.Equal(t, filterCapNotice, frame.Meta.Notices[1].Text)
+
+	// The notices carry over to the time series the records are bucketed into
+	resp = ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"incrementalUsers","format":"time_series","advancedFilters":[{"field":"role","operator":"equals","value":"agent"}]}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+		Interval:  time.Hour,
+	})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames[0].Meta.Notices, 2)
+}
+
+func TestFilterExpr_MemoryOnly(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		filters []AdvancedFilter
+		want    bool
+	}{
+		{"pushed equality", []AdvancedFilter{{Field: "status", Operator: operatorEquals, Value: "open"}}, false},
+		{"contains", []AdvancedFilter{{Field: "status", Operator: operatorContains, Value: "op"}}, true},
+		{"no keyword", []AdvancedFilter{{Field: "subject", Operator: operatorEquals, Value: "hi"}}, true},
+		{"OR groups", []AdvancedFilter{
+			{Field: "status", Operator: operatorEquals, Value: "open"},
+			{Field: "status", Operator: operatorEquals, Value: "new", Logic: logicOr},
+		}, true},
+	} {
+		assert.Equal(t, tc.want, compileFilters(tc.filters).memoryOnly(zendesk.SearchTypeTicket), tc.name)
+	}
+	assert.True(t, compileFilters([]AdvancedFilter{{Field: "status", Operator: operatorEquals, Value: "open"}}).memoryOnly(""))
+}
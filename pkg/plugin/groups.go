@@ -0,0 +1,199 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// groupsCacheKey caches the groups of the account, shared by group queries,
+// ticket enrichment and the groups resource
+const groupsCacheKey = "groups"
+
+// groupsResult holds groups and, when requested, their memberships
+type groupsResult struct {
+	Groups      []zendesk.Group
+	Memberships []zendesk.GroupMembership
+}
+
+// variableOption is a dashboard variable value returned by resource endpoints
+type variableOption struct {
+	Text  string `json:"text"`
+	Value int64  `json:"value"`
+}
+
+// listGroups returns the groups of the account, from cache when possible
+func (ds *Datasource) listGroups(ctx context.Context) ([]zendesk.Group, error) {
+	if cached, found := ds.cacheManager.Get(groupsCacheKey); found {
+		if groups, ok := cached.([]zendesk.Group); ok {
+			return groups, nil
+		}
+	}
+
+	groups, err := ds.zendeskClient.ListGroups(ctx, maxQueryRows)
+	if err != nil {
+		return nil, err
+	}
+	ds.cacheManager.Set(groupsCacheKey, groups, cache.DefaultConfig().DefaultTTL)
+	return groups, nil
+}
+
+// groupNames maps group ids to names
+func groupNames(groups []zendesk.Group) map[int64]string {
+	names := make(map[int64]string, len(groups))
+	for _, group := range groups {
+		names[group.ID] = group.Name
+	}
+	return names
+}
+
+// ticketGroupNames returns the names of the groups tickets are assigned to
+func (ds *Datasource) ticketGroupNames(ctx context.Context, tickets []zendesk.Ticket, sideloaded map[int64]zendesk.Group) (map[int64]string, error) {
+	var ids []int64
+	for _, ticket := range tickets {
+		if ticket.GroupID != nil {
+			ids = append(ids, *ticket.GroupID)
+		}
+	}
+	return ds.groupNamesByID(ctx, ids, sideloaded)
+}
+
+// groupNamesByID returns the names of the groups ids, from side-loaded groups
+// or the name cache, listing every group only when one is missing. Names left
+// empty are reported by err.
+func (ds *Datasource) groupNamesByID(ctx context.Context, ids []int64, sideloaded map[int64]zendesk.Group) (map[int64]string, error) {
+	names := make(map[int64]string, len(sideloaded))
+	for id, group := range sideloaded {
+		names[id] = group.Name
+	}
+	if len(ds.cachedNames("group", ids, names)) == 0 {
+		return names, nil
+	}
+
+	groups, err := ds.listGroups(ctx)
+	if err != nil {
+		return names, fmt.Errorf("failed to fetch group names: %w", err)
+	}
+	fetched := groupNames(groups)
+	for id, name := range fetched {
+		names[id] = name
+	}
+	ds.cacheNames("group", fetched)
+	return names, nil
+}
+
+// queryGroups handles group queries; includeMemberships adds a frame of
+// the agents in each group
+func (ds *Datasource) queryGroups(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	includeMemberships := model.IncludeMemberships
+
+	groups, err := ds.listGroups(ctx)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch groups: %w", err),
+		}
+	}
+	result := &groupsResult{Groups: groups}
+
+	if includeMemberships {
+		// Check cache first
+		cacheKey := "groupMemberships"
+		if cached, found := ds.cacheManager.Get(cacheKey); found {
+			result.Memberships, _ = cached.([]zendesk.GroupMembership)
+		}
+
+		// Fetch from API
+		if result.Memberships == nil {
+			result.Memberships, err = ds.zendeskClient.ListGroupMemberships(ctx, 0, maxQueryRows)
+			if err != nil {
+				return &backend.DataResponse{
+					Error: fmt.Errorf("failed to fetch group memberships: %w", err),
+				}
+			}
+
+			// Cache the result
+			ds.cacheManager.Set(cacheKey, result.Memberships, cache.DefaultConfig().DefaultTTL)
+		}
+	}
+
+	return ds.groupsToDataFrames(result, includeMemberships)
+}
+
+// groupsToDataFrames converts groups, and optionally their memberships, to Grafana DataFrames
+func (ds *Datasource) groupsToDataFrames(result *groupsResult, includeMemberships bool) *backend.DataResponse {
+	frame := data.NewFrame("groups")
+	frame.Fields = append(frame.Fields,
+		data.NewField("id", nil, []int64{}),
+		data.NewField("name", nil, []string{}),
+		data.NewField("description", nil, []string{}),
+		data.NewField("default", nil, []bool{}),
+		data.NewField("is_public", nil, []bool{}),
+		data.NewField("created_at", nil, []time.Time{}),
+	)
+
+	for _, group := range result.Groups {
+		description := ""
+		if group.Description != nil {
+			description = *group.Description
+		}
+		frame.AppendRow(
+			group.ID,
+			group.Name,
+			description,
+			group.Default,
+			group.IsPublic,
+			group.CreatedAt,
+		)
+	}
+
+	frames := data.Frames{frame}
+	if includeMemberships {
+		names := groupNames(result.Groups)
+		memberships := data.NewFrame("group_memberships")
+		memberships.Fields = append(memberships.Fields,
+			data.NewField("group_id", nil, []int64{}),
+			data.NewField("group_name", nil, []string{}),
+			data.NewField("user_id", nil, []int64{}),
+			data.NewField("default", nil, []bool{}),
+		)
+		for _, membership := range result.Memberships {
+			memberships.AppendRow(
+				membership.GroupID,
+				names[membership.GroupID],
+				membership.UserID,
+				membership.Default,
+			)
+		}
+		frames = append(frames, memberships)
+	}
+
+	return &backend.DataResponse{
+		Frames: frames,
+	}
+}
+
+// handleGroups lists groups as dashboard variable options, sorted by name
+func (ds *Datasource) handleGroups(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	groups, err := ds.listGroups(ctx)
+	if err != nil {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: 500,
+			Body:   []byte(fmt.Sprintf(`{"error":"Failed to fetch groups: %v"}`, err)),
+		})
+	}
+
+	options := make([]variableOption, 0, len(groups))
+	for _, group := range groups {
+		options = append(options, variableOption{Text: group.Name, Value: group.ID})
+	}
+	sort.SliceStable(options, func(i, j int) bool { return options[i].Text < options[j].Text })
+
+	return sendJSON(sender, options)
+}
@@ -0,0 +1,84 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// responseRecorder captures the response sent by a resource handler
+type responseRecorder struct {
+	resp *backend.CallResourceResponse
+}
+
+func (r *responseRecorder) Send(resp *backend.CallResourceResponse) error {
+	r.resp = resp
+	return nil
+}
+
+const testGroups = `{"groups":[{"id":1,"name":"Tier 2","created_at":"2024-01-01T09:00:00Z"},{"id":2,"name":"Billing","is_public":true}],"meta":{"has_more":false}}`
+
+func TestQueryGroups_IncludeMemberships(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/groups.json":
+			fmt.Fprint(w, testGroups)
+		case "/api/v2/group_memberships.json":
+			fmt.Fprint(w, `{"group_memberships":[{"user_id":7,"group_id":2,"default":true}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"groups","includeMemberships":true}`)})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 2)
+	assert.Equal(t, 2, resp.Frames[0].Rows())
+	createdAt, _ := resp.Frames[0].FieldByName("created_at")
+	assert.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), createdAt.At(0))
+
+	groupName, _ := resp.Frames[1].FieldByName("group_name")
+	assert.Equal(t, "Billing", groupName.At(0))
+}
+
+func TestQueryTickets_GroupNames(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/groups.json":
+			fmt.Fprint(w, testGroups)
+		case "/api/v2/tickets.json":
+			fmt.Fprint(w, `{"tickets":[{"id":1,"status":"open","group_id":2},{"id":2,"status":"new"}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets"}`)})
+	require.NoError(t, resp.Error)
+	groupName, _ := resp.Frames[0].FieldByName("group_name")
+	assert.Equal(t, "Billing", groupName.At(0))
+	assert.Equal(t, "", groupName.At(1))
+}
+
+func TestCallResource_Groups(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testGroups)
+	}))
+
+	recorder := &responseRecorder{}
+	err := ds.CallResource(context.Background(), &backend.CallResourceRequest{Path: "groups"}, recorder)
+	require.NoError(t, err)
+	resp := recorder.resp
+	require.Equal(t, 200, resp.Status)
+
+	var options []variableOption
+	require.NoError(t, json.Unmarshal(resp.Body, &options))
+	assert.Equal(t, []variableOption{{Text: "Billing", Value: 2}, {Text: "Tier 2", Value: 1}}, options)
+}
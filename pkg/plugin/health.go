@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -25,7 +26,7 @@ func (ds *Datasource) CheckHealth(ctx context.Context, req *backend.CheckHealthR
 
 	// Test Zendesk API connection
 	start := time.Now()
-	if err := ds.zendeskClient.TestConnection(); err != nil {
+	if err := ds.zendeskClient.TestConnection(ctx); err != nil {
 		status.Status = "error"
 		status.Message = fmt.Sprintf("Zendesk API connection failed: %v", err)
 		return &backend.CheckHealthResult{
@@ -55,10 +56,12 @@ func (ds *Datasource) CheckHealth(ctx context.Context, req *backend.CheckHealthR
 			if ds.cacheManager != nil {
 				cacheSize = ds.cacheManager.GetStats().Size
 			}
-			return []byte(fmt.Sprintf(`{
-			"cache_size": %d,
-			"api_latency_ms": %.2f
-		}`, cacheSize, float64(status.APILatency.Nanoseconds())/1e6))
+			details, _ := json.Marshal(map[string]interface{}{
+				"cache_size":     cacheSize,
+				"api_latency_ms": float64(status.APILatency.Nanoseconds()) / 1e6,
+				"rate_limit":     ds.zendeskClient.RateLimitStats(),
+			})
+			return details
 		}(),
 	}, nil
 }
@@ -0,0 +1,172 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// incrementalRowCapNotice tells that an export stopped at maxQueryRows records
+var incrementalRowCapNotice = fmt.Sprintf("Only the first %d records in the time range are shown; narrow the time range to see the rest.", maxQueryRows)
+
+// errIncrementalRowCap stops an export once maxQueryRows records matched the time range
+var errIncrementalRowCap = errors.New("incremental export row cap reached")
+
+// incrementalOptions builds export options covering the query time range.
+// Exports are ordered by update time, which is never before creation, so the
+// range start is pushed down for either time field. Records created in the
+// range may have been updated after it, so created_at walks past the range end
+// without a row cap; the callers cap the records matching the range instead.
+func incrementalOptions(query backend.DataQuery, model *QueryModel, filter *timeFilter) zendesk.IncrementalOptions {
+	opts := zendesk.IncrementalOptions{
+		StartTime:      query.TimeRange.From,
+		EndTime:        query.TimeRange.To,
+		MaxRows:        maxQueryRows,
+		IncludeDeleted: model.IncludeDeleted,
+	}
+	if filter.Field == timeFieldCreatedAt {
+		opts.EndTime = time.Time{}
+		opts.MaxRows = 0
+	}
+	return opts
+}
+
+// incrementalRows returns how many of the matched records to keep, and
+// errIncrementalRowCap once kept and matched reach maxQueryRows
+func incrementalRows(kept, matched int) (int, error) {
+	if kept+matched < maxQueryRows {
+		return matched, nil
+	}
+	return maxQueryRows - kept, errIncrementalRowCap
+}
+
+// incrementalResponse notes on the frames of resp when rows records reached maxQueryRows
+func incrementalResponse(resp *backend.DataResponse, rows int) *backend.DataResponse {
+	if rows < maxQueryRows || resp.Error != nil {
+		return resp
+	}
+	for _, frame := range resp.Frames {
+		frame.AppendNotices(data.Notice{Severity: data.NoticeSeverityWarning, Text: incrementalRowCapNotice})
+	}
+	return resp
+}
+
+// incrementalCacheKey returns the cache key for an incremental export over the query time range
+func incrementalCacheKey(resource string, opts zendesk.IncrementalOptions, filter *timeFilter) string {
+	return fmt.Sprintf("incremental:%s:%d:%d:%t:%s", resource, opts.StartTime.Unix(), opts.EndTime.Unix(), opts.IncludeDeleted, filter.key())
+}
+
+// queryIncrementalTickets handles incremental ticket export queries
+func (ds *Datasource) queryIncrementalTickets(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldUpdatedAt, timeFieldCreatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+	opts := incrementalOptions(query, model, filter)
+
+	// Check cache first
+	cacheKey := incrementalCacheKey("tickets", opts, filter)
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if tickets, ok := cached.([]zendesk.Ticket); ok {
+			return incrementalResponse(ds.ticketsResponse(ctx, tickets, nil, model), len(tickets))
+		}
+	}
+
+	// Fetch from API
+	var tickets []zendesk.Ticket
+	_, err = ds.zendeskClient.IncrementalTickets(ctx, opts, func(page []zendesk.Ticket) error {
+		matched := filter.tickets(page)
+		rows, err := incrementalRows(len(tickets), len(matched))
+		tickets = append(tickets, matched[:rows]...)
+		return err
+	})
+	if err != nil && !errors.Is(err, errIncrementalRowCap) {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to export tickets: %w", err),
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, tickets, cache.DefaultConfig().DefaultTTL)
+
+	return incrementalResponse(ds.ticketsResponse(ctx, tickets, nil, model), len(tickets))
+}
+
+// queryIncrementalUsers handles incremental user export queries
+func (ds *Datasource) queryIncrementalUsers(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldUpdatedAt, timeFieldCreatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+	opts := incrementalOptions(query, model, filter)
+
+	// Check cache first
+	cacheKey := incrementalCacheKey("users", opts, filter)
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if users, ok := cached.([]zendesk.User); ok {
+			return incrementalResponse(ds.usersToDataFrame(users), len(users))
+		}
+	}
+
+	// Fetch from API
+	var users []zendesk.User
+	_, err = ds.zendeskClient.IncrementalUsers(ctx, opts, func(page []zendesk.User) error {
+		matched := filter.users(page)
+		rows, err := incrementalRows(len(users), len(matched))
+		users = append(users, matched[:rows]...)
+		return err
+	})
+	if err != nil && !errors.Is(err, errIncrementalRowCap) {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to export users: %w", err),
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, users, cache.DefaultConfig().DefaultTTL)
+
+	return incrementalResponse(ds.usersToDataFrame(users), len(users))
+}
+
+// queryIncrementalOrganizations handles incremental organization export queries
+func (ds *Datasource) queryIncrementalOrganizations(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldUpdatedAt, timeFieldCreatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+	opts := incrementalOptions(query, model, filter)
+
+	// Check cache first
+	cacheKey := incrementalCacheKey("organizations", opts, filter)
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if orgs, ok := cached.([]zendesk.Organization); ok {
+			return incrementalResponse(ds.organizationsToDataFrame(orgs), len(orgs))
+		}
+	}
+
+	// Fetch from API
+	var orgs []zendesk.Organization
+	_, err = ds.zendeskClient.IncrementalOrganizations(ctx, opts, func(page []zendesk.Organization) error {
+		matched := filter.organizations(page)
+		rows, err := incrementalRows(len(orgs), len(matched))
+		orgs = append(orgs, matched[:rows]...)
+		return err
+	})
+	if err != nil && !errors.Is(err, errIncrementalRowCap) {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to export organizations: %w", err),
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, orgs, cache.DefaultConfig().DefaultTTL)
+
+	return incrementalResponse(ds.organizationsToDataFrame(orgs), len(orgs))
+}
@@ -0,0 +1,63 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryIncrementalUsers_UpdatedAtByDefault(t *testing.T) {
+	var requests int
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/v2/incremental/users/cursor.json", r.URL.Path)
+		fmt.Fprintf(w, `{"users":[
+			{"id":1,"active":true,"created_at":"2023-06-01T00:00:00Z","updated_at":"2024-01-01T00:10:00Z"},
+			{"id":2,"active":true,"created_at":"2024-01-01T00:20:00Z","updated_at":"2024-01-01T02:00:00Z"}],
+			"after_url":"http://%s/api/v2/incremental/users/cursor.json?cursor=next","end_of_stream":false}`, r.Host)
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"incrementalUsers"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	// The export stops at the first user updated after the range
+	assert.Equal(t, 1, requests)
+	id, _ := resp.Frames[0].FieldByName("id")
+	require.Equal(t, 1, id.Len())
+	assert.Equal(t, int64(1), id.At(0))
+	assert.Nil(t, resp.Frames[0].Meta)
+}
+
+func TestQueryIncrementalUsers_CreatedAtRowCap(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Users created before the range come first and do not count towards the cap
+		users := []string{`{"id":1,"active":true,"created_at":"2023-06-01T00:00:00Z","updated_at":"2024-01-01T00:00:00Z"}`}
+		for id := 2; id <= maxQueryRows+10; id++ {
+			users = append(users, fmt.Sprintf(`{"id":%d,"active":true,"created_at":"2024-01-01T00:10:00Z","updated_at":"2024-01-01T02:00:00Z"}`, id))
+		}
+		fmt.Fprintf(w, `{"users":[%s],"end_of_stream":true}`, strings.Join(users, ","))
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"incrementalUsers","timeField":"created_at"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	assert.Equal(t, maxQueryRows, frame.Rows())
+	assert.Equal(t, int64(2), frame.Fields[0].At(0))
+	require.NotNil(t, frame.Meta)
+	require.Len(t, frame.Meta.Notices, 1)
+	assert.Equal(t, incrementalRowCapNotice, frame.Meta.Notices[0].Text)
+}
@@ -0,0 +1,244 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// ticketIncludes are the records side-loaded with ticket lists and searches
+var ticketIncludes = []string{zendesk.IncludeUsers, zendesk.IncludeGroups, zendesk.IncludeOrganizations}
+
+// ticketLookups holds what ticket frames resolve beyond the ticket itself:
+// user, group and organization names, the custom fields to add as columns
+// and, for queries by solved_at, the solve times
+type ticketLookups struct {
+	Users         map[int64]string
+	Groups        map[int64]string
+	Organizations map[int64]string
+	CustomFields  []customField
+	SolvedAt      map[int64]*time.Time
+	// Incomplete is set when names were left empty after a failed lookup
+	Incomplete bool
+}
+
+// ticketsResult holds tickets with the records side-loaded with them
+type ticketsResult struct {
+	Tickets   []zendesk.Ticket
+	Sideloads *zendesk.Sideloads
+}
+
+// nameCacheTTL keeps resolved user, group and organization names for longer
+// than query results, as they rarely change
+const nameCacheTTL = time.Hour
+
+// lookupNotice tells that some names were left empty after a failed lookup
+const lookupNotice = "Some user, group or organization names could not be fetched and are left empty."
+
+// nameCacheKey is the cache key of the name of the kind record id
+func nameCacheKey(kind string, id int64) string {
+	return fmt.Sprintf("name:%s:%d", kind, id)
+}
+
+// cachedNames adds the cached names of the kind records ids to names and
+// returns the ids still missing
+func (ds *Datasource) cachedNames(kind string, ids []int64, names map[int64]string) []int64 {
+	var missing []int64
+	seen := make(map[int64]bool)
+	for _, id := range ids {
+		if _, ok := names[id]; ok || id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if cached, found := ds.cacheManager.Get(nameCacheKey(kind, id)); found {
+			if name, ok := cached.(string); ok {
+				names[id] = name
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+	return missing
+}
+
+// cacheNames caches the names of kind records for nameCacheTTL
+func (ds *Datasource) cacheNames(kind string, names map[int64]string) {
+	for id, name := range names {
+		ds.cacheManager.Set(nameCacheKey(kind, id), name, nameCacheTTL)
+	}
+}
+
+// lookupError returns the error of a name lookup that must fail the query:
+// cancellation and authentication or permission errors. Other errors only
+// leave names empty.
+func lookupError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if errors.Is(err, zendesk.ErrUnauthorized) || errors.Is(err, zendesk.ErrForbidden) {
+		return err
+	}
+	return nil
+}
+
+// userNames returns the names of the users ids, from side-loaded users, the
+// name cache or fetched in bulk. Names left empty are reported by err.
+func (ds *Datasource) userNames(ctx context.Context, ids []int64, sideloaded map[int64]zendesk.User) (map[int64]string, error) {
+	names := make(map[int64]string, len(sideloaded))
+	for id, user := range sideloaded {
+		names[id] = user.Name
+	}
+
+	missing := ds.cachedNames("user", ids, names)
+	if len(missing) == 0 {
+		return names, nil
+	}
+
+	users, err := ds.zendeskClient.ShowManyUsers(ctx, missing)
+	if err != nil {
+		return names, fmt.Errorf("failed to fetch user names: %w", err)
+	}
+	fetched := make(map[int64]string, len(users))
+	for _, user := range users {
+		fetched[user.ID] = user.Name
+		names[user.ID] = user.Name
+	}
+	ds.cacheNames("user", fetched)
+	return names, nil
+}
+
+// ticketUserNames returns the names of the requesters and assignees of tickets
+func (ds *Datasource) ticketUserNames(ctx context.Context, tickets []zendesk.Ticket, sideloaded map[int64]zendesk.User) (map[int64]string, error) {
+	var ids []int64
+	for _, ticket := range tickets {
+		ids = append(ids, ticket.RequesterID)
+		if ticket.AssigneeID != nil {
+			ids = append(ids, *ticket.AssigneeID)
+		}
+	}
+	return ds.userNames(ctx, ids, sideloaded)
+}
+
+// ticketOrganizationNames returns the names of the organizations of tickets,
+// from side-loaded organizations, the name cache or fetched in bulk. Names
+// left empty are reported by err.
+func (ds *Datasource) ticketOrganizationNames(ctx context.Context, tickets []zendesk.Ticket, sideloaded map[int64]zendesk.Organization) (map[int64]string, error) {
+	names := make(map[int64]string, len(sideloaded))
+	for id, org := range sideloaded {
+		names[id] = org.Name
+	}
+
+	var ids []int64
+	for _, ticket := range tickets {
+		if ticket.OrganizationID != nil {
+			ids = append(ids, *ticket.OrganizationID)
+		}
+	}
+	missing := ds.cachedNames("organization", ids, names)
+	if len(missing) == 0 {
+		return names, nil
+	}
+
+	orgs, err := ds.zendeskClient.ShowManyOrganizations(ctx, missing)
+	if err != nil {
+		return names, fmt.Errorf("failed to fetch organization names: %w", err)
+	}
+	fetched := make(map[int64]string, len(orgs))
+	for _, org := range orgs {
+		fetched[org.ID] = org.Name
+		names[org.ID] = org.Name
+	}
+	ds.cacheNames("organization", fetched)
+	return names, nil
+}
+
+// ticketLookups resolves the names referenced by tickets, preferring
+// side-loaded records, and the custom fields chosen by the customFields
+// query option
+func (ds *Datasource) ticketLookups(ctx context.Context, tickets []zendesk.Ticket, sideloads *zendesk.Sideloads, model *QueryModel) (*ticketLookups, error) {
+	if sideloads == nil {
+		sideloads = &zendesk.Sideloads{}
+	}
+	lookups := &ticketLookups{}
+	var userErr, groupErr, orgErr error
+	lookups.Users, userErr = ds.ticketUserNames(ctx, tickets, sideloads.Users)
+	lookups.Groups, groupErr = ds.ticketGroupNames(ctx, tickets, sideloads.Groups)
+	lookups.Organizations, orgErr = ds.ticketOrganizationNames(ctx, tickets, sideloads.Organizations)
+	for _, err := range []error{userErr, groupErr, orgErr} {
+		if err == nil {
+			continue
+		}
+		if fatal := lookupError(ctx, err); fatal != nil {
+			return nil, fatal
+		}
+		lookups.Incomplete = true
+	}
+
+	var err error
+	lookups.CustomFields, err = ds.ticketCustomFields(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+	if model.TimeField == timeFieldSolvedAt {
+		lookups.SolvedAt, err = ds.ticketSolvedTimes(ctx, tickets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch ticket metrics: %w", err)
+		}
+	}
+	return lookups, nil
+}
+
+// solvedAtCacheKey is the cache key of the solve time of a ticket
+func solvedAtCacheKey(ticketID int64) string {
+	return fmt.Sprintf("solvedAt:%d", ticketID)
+}
+
+// ticketSolvedTimes returns the solve times of tickets, read from their
+// metrics as tickets do not carry them. Unsolved tickets map to nil.
+func (ds *Datasource) ticketSolvedTimes(ctx context.Context, tickets []zendesk.Ticket) (map[int64]*time.Time, error) {
+	solved := make(map[int64]*time.Time, len(tickets))
+	var missing []int64
+	for _, ticket := range tickets {
+		if cached, found := ds.cacheManager.Get(solvedAtCacheKey(ticket.ID)); found {
+			if at, ok := cached.(*time.Time); ok {
+				solved[ticket.ID] = at
+				continue
+			}
+		}
+		missing = append(missing, ticket.ID)
+	}
+	if len(missing) == 0 {
+		return solved, nil
+	}
+
+	_, metrics, err := ds.zendeskClient.ShowManyTicketsWithMetrics(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, metric := range metrics {
+		solved[metric.TicketID] = metric.SolvedAt
+	}
+
+	// A reopened ticket is solved again, so solve times expire with query results
+	for _, id := range missing {
+		ds.cacheManager.Set(solvedAtCacheKey(id), solved[id], cache.DefaultConfig().DefaultTTL)
+	}
+	return solved, nil
+}
+
+// ticketsResponse converts tickets to a frame with the lookups of the query
+func (ds *Datasource) ticketsResponse(ctx context.Context, tickets []zendesk.Ticket, sideloads *zendesk.Sideloads, model *QueryModel) *backend.DataResponse {
+	lookups, err := ds.ticketLookups(ctx, tickets, sideloads, model)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: err,
+		}
+	}
+	return ds.ticketsToDataFrame(tickets, lookups)
+}
@@ -0,0 +1,146 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTickets_SideloadedNames(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets.json", r.URL.Path)
+		assert.Equal(t, "users,groups,organizations", r.URL.Query().Get("include"))
+		fmt.Fprint(w, `{"tickets":[{"id":1,"status":"open","requester_id":7,"assignee_id":8,"organization_id":3,"group_id":4}],
+			"users":[{"id":7,"name":"Ana"},{"id":8,"name":"Ben"}],
+			"groups":[{"id":4,"name":"Billing"}],
+			"organizations":[{"id":3,"name":"Acme"}],
+			"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets"}`)})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+
+	for name, want := range map[string]string{
+		"requester_name":    "Ana",
+		"assignee_name":     "Ben",
+		"organization_name": "Acme",
+		"group_name":        "Billing",
+	} {
+		field, _ := frame.FieldByName(name)
+		require.NotNil(t, field, name)
+		assert.Equal(t, want, field.At(0), name)
+	}
+}
+
+func TestQueryIncrementalTickets_ShowManyNames(t *testing.T) {
+	var userRequests int
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/incremental/tickets/cursor.json":
+			fmt.Fprint(w, `{"tickets":[{"id":1,"requester_id":7,"assignee_id":8,"created_at":"2024-01-01T00:10:00Z","updated_at":"2024-01-01T00:10:00Z"},{"id":2,"requester_id":8,"organization_id":3,"created_at":"2024-01-01T00:20:00Z","updated_at":"2024-01-01T00:20:00Z"}],"end_of_stream":true}`)
+		case "/api/v2/users/show_many.json":
+			userRequests++
+			assert.Equal(t, "7,8", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"users":[{"id":7,"name":"Ana"},{"id":8,"name":"Ben"}]}`)
+		case "/api/v2/organizations/show_many.json":
+			fmt.Fprint(w, `{"organizations":[{"id":3,"name":"Acme"}]}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := backend.DataQuery{
+		JSON:      []byte(`{"queryType":"incrementalTickets"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+	}
+	resp := ds.handleQuery(context.Background(), query)
+	require.NoError(t, resp.Error)
+	// Fetched names are cached for the next query
+	resp = ds.handleQuery(context.Background(), query)
+	require.NoError(t, resp.Error)
+	assert.Equal(t, 1, userRequests)
+
+	requester, _ := resp.Frames[0].FieldByName("requester_name")
+	organization, _ := resp.Frames[0].FieldByName("organization_name")
+	assert.Equal(t, "Ben", requester.At(1))
+	assert.Equal(t, "Acme", organization.At(1))
+}
+
+func TestQueryIncrementalTickets_NameLookupErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"unauthorized fails the query", http.StatusUnauthorized, true},
+		{"forbidden fails the query", http.StatusForbidden, true},
+		{"server error leaves names empty", http.StatusInternalServerError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v2/incremental/tickets/cursor.json":
+					fmt.Fprint(w, `{"tickets":[{"id":1,"requester_id":7,"created_at":"2024-01-01T00:10:00Z","updated_at":"2024-01-01T00:10:00Z"}],"end_of_stream":true}`)
+				case "/api/v2/users/show_many.json":
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, `{"error":"failed"}`)
+				default:
+					t.Errorf("unexpected request: %s", r.URL.Path)
+				}
+			}))
+
+			from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			resp := ds.handleQuery(context.Background(), backend.DataQuery{
+				JSON:      []byte(`{"queryType":"incrementalTickets"}`),
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			})
+			if tt.wantErr {
+				assert.Error(t, resp.Error)
+				return
+			}
+			require.NoError(t, resp.Error)
+			frame := resp.Frames[0]
+			requester, _ := frame.FieldByName("requester_name")
+			assert.Equal(t, "", requester.At(0))
+			require.NotNil(t, frame.Meta)
+			require.Len(t, frame.Meta.Notices, 1)
+			assert.Equal(t, lookupNotice, frame.Meta.Notices[0].Text)
+		})
+	}
+}
+
+func TestQueryTickets_TimeColumns(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tickets":[
+			{"id":1,"status":"open","created_at":"2024-03-01T09:30:00Z","updated_at":"2024-03-02T10:00:00Z","due_at":"2024-03-05T00:00:00Z","via":{"channel":"web"},"tags":["a","b"]},
+			{"id":2,"status":"new","created_at":"2024-03-03T08:00:00Z","updated_at":"2024-03-03T08:00:00Z"}],
+			"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets"}`)})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+
+	created, _ := frame.FieldByName("created_at")
+	require.NotNil(t, created)
+	assert.Equal(t, time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), created.At(0))
+
+	due, _ := frame.FieldByName("due_at")
+	require.NotNil(t, due)
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), *due.At(0).(*time.Time))
+	assert.Nil(t, due.At(1))
+
+	channel, _ := frame.FieldByName("via_channel")
+	assert.Equal(t, "web", channel.At(0))
+	tags, _ := frame.FieldByName("tags")
+	assert.Equal(t, "a b", tags.At(0))
+}
@@ -0,0 +1,191 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// ticketMetricsResult holds ticket metrics and, when side-loaded, their tickets
+type ticketMetricsResult struct {
+	Metrics []zendesk.TicketMetric
+	Tickets []zendesk.Ticket
+}
+
+// queryTicketMetrics handles ticket metric queries. A ticketId limits the
+// result to one ticket and ignores the time range; includeTickets side-loads
+// ticket attributes. With a time range the tickets are found by search first,
+// so the row cap applies to tickets within the range.
+func (ds *Datasource) queryTicketMetrics(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	ticketID := model.TicketID
+	includeTickets := model.IncludeTickets
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt, timeFieldSolvedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("ticketMetrics:%d:%t:%s", ticketID, includeTickets, filter.key())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if result, ok := cached.(*ticketMetricsResult); ok {
+			return ds.ticketMetricsToDataFrame(result)
+		}
+	}
+
+	// Fetch from API
+	result := &ticketMetricsResult{}
+	switch {
+	case ticketID > 0 && includeTickets:
+		result.Tickets, result.Metrics, err = ds.zendeskClient.ShowManyTicketsWithMetrics(ctx, []int64{ticketID})
+	case ticketID > 0:
+		var metric *zendesk.TicketMetric
+		metric, err = ds.zendeskClient.GetTicketMetric(ctx, ticketID)
+		if err == nil {
+			result.Metrics = []zendesk.TicketMetric{*metric}
+		}
+	case filter.active():
+		var results *zendesk.SearchResults
+		results, err = ds.zendeskClient.Search(ctx, filter.searchTerms(), zendesk.SearchTypeTicket, maxQueryRows)
+		if err == nil {
+			ids := make([]int64, 0, len(results.Tickets))
+			for _, ticket := range results.Tickets {
+				ids = append(ids, ticket.ID)
+			}
+			result.Tickets, result.Metrics, err = ds.zendeskClient.ShowManyTicketsWithMetrics(ctx, ids)
+		}
+	case includeTickets:
+		result.Tickets, result.Metrics, err = ds.zendeskClient.ListTicketsWithMetrics(ctx, nil, maxQueryRows)
+	default:
+		result.Metrics, err = ds.zendeskClient.ListTicketMetrics(ctx, maxQueryRows)
+	}
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch ticket metrics: %w", err),
+		}
+	}
+	if ticketID == 0 {
+		result.Metrics = filter.ticketMetrics(result.Metrics)
+	}
+	if includeTickets {
+		result.Tickets = metricTickets(result.Tickets, result.Metrics)
+	} else {
+		result.Tickets = nil
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, result, cache.DefaultConfig().DefaultTTL)
+
+	return ds.ticketMetricsToDataFrame(result)
+}
+
+// metricTickets keeps the tickets that metrics belong to
+func metricTickets(tickets []zendesk.Ticket, metrics []zendesk.TicketMetric) []zendesk.Ticket {
+	ids := make(map[int64]bool, len(metrics))
+	for _, metric := range metrics {
+		ids[metric.TicketID] = true
+	}
+	kept := make([]zendesk.Ticket, 0, len(metrics))
+	for _, ticket := range tickets {
+		if ids[ticket.ID] {
+			kept = append(kept, ticket)
+		}
+	}
+	return kept
+}
+
+// minutes converts an optional minute count to a nullable frame value
+func minutes(v *int64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// metricDurations are the MetricDurations of a ticket metric; each becomes a
+// calendar and a business column
+var metricDurations = []struct {
+	name  string
+	value func(m *zendesk.TicketMetric) zendesk.MetricDuration
+}{
+	{"first_reply_time", func(m *zendesk.TicketMetric) zendesk.MetricDuration { return m.ReplyTimeInMinutes }},
+	{"first_resolution_time", func(m *zendesk.TicketMetric) zendesk.MetricDuration { return m.FirstResolutionTimeInMinutes }},
+	{"full_resolution_time", func(m *zendesk.TicketMetric) zendesk.MetricDuration { return m.FullResolutionTimeInMinutes }},
+	{"agent_wait_time", func(m *zendesk.TicketMetric) zendesk.MetricDuration { return m.AgentWaitTimeInMinutes }},
+	{"requester_wait_time", func(m *zendesk.TicketMetric) zendesk.MetricDuration { return m.RequesterWaitTimeInMinutes }},
+	{"on_hold_time", func(m *zendesk.TicketMetric) zendesk.MetricDuration { return m.OnHoldTimeInMinutes }},
+}
+
+// ticketMetricsColumns returns the columns of the ticket metrics frame
+func ticketMetricsColumns(includeTickets bool) []string {
+	columns := []string{"ticket_id", "reopens", "replies", "created_at", "solved_at"}
+	for _, d := range metricDurations {
+		columns = append(columns, d.name+"_calendar", d.name+"_business")
+	}
+	if includeTickets {
+		columns = append(columns, "subject", "status")
+	}
+	return columns
+}
+
+// ticketMetricsToDataFrame converts ticket metrics to Grafana DataFrame with
+// durations in minutes, in calendar and business hours
+func (ds *Datasource) ticketMetricsToDataFrame(result *ticketMetricsResult) *backend.DataResponse {
+	frame := data.NewFrame("ticket_metrics")
+	frame.Fields = append(frame.Fields,
+		data.NewField("ticket_id", nil, []int64{}),
+		data.NewField("reopens", nil, []int64{}),
+		data.NewField("replies", nil, []int64{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("solved_at", nil, []*time.Time{}),
+	)
+
+	for _, d := range metricDurations {
+		calendar := data.NewField(d.name+"_calendar", nil, []*float64{})
+		calendar.Config = &data.FieldConfig{Unit: "m"}
+		business := data.NewField(d.name+"_business", nil, []*float64{})
+		business.Config = &data.FieldConfig{Unit: "m"}
+		frame.Fields = append(frame.Fields, calendar, business)
+	}
+
+	// Side-loaded tickets add their attributes next to the metrics
+	var tickets map[int64]zendesk.Ticket
+	if result.Tickets != nil {
+		tickets = make(map[int64]zendesk.Ticket, len(result.Tickets))
+		for _, ticket := range result.Tickets {
+			tickets[ticket.ID] = ticket
+		}
+		frame.Fields = append(frame.Fields,
+			data.NewField("subject", nil, []string{}),
+			data.NewField("status", nil, []string{}),
+		)
+	}
+
+	for i := range result.Metrics {
+		metric := &result.Metrics[i]
+		row := []interface{}{metric.TicketID, metric.Reopens, metric.Replies, metric.CreatedAt, metric.SolvedAt}
+		for _, d := range metricDurations {
+			duration := d.value(metric)
+			row = append(row, minutes(duration.Calendar), minutes(duration.Business))
+		}
+		if tickets != nil {
+			ticket := tickets[metric.TicketID]
+			subject := ""
+			if ticket.Subject != nil {
+				subject = *ticket.Subject
+			}
+			row = append(row, subject, ticket.Status)
+		}
+		frame.AppendRow(row...)
+	}
+
+	return &backend.DataResponse{
+		Frames: data.Frames{frame},
+	}
+}
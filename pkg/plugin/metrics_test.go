@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTicketMetrics(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/ticket_metrics.json", r.URL.Path)
+		fmt.Fprint(w, `{"ticket_metrics":[
+			{"ticket_id":1,"reopens":1,"replies":3,"reply_time_in_minutes":{"calendar":90,"business":30},"full_resolution_time_in_minutes":{"calendar":600}},
+			{"ticket_id":2,"replies":0}
+		],"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"ticketMetrics"}`)})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	require.Equal(t, 2, frame.Rows())
+
+	reply, _ := frame.FieldByName("first_reply_time_business")
+	assert.Equal(t, 30.0, *reply.At(0).(*float64))
+	resolution, _ := frame.FieldByName("full_resolution_time_business")
+	assert.Nil(t, resolution.At(0))
+	reopens, _ := frame.FieldByName("reopens")
+	assert.Equal(t, int64(0), reopens.At(1))
+}
+
+func TestQueryTicketMetrics_SideloadsTickets(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets.json", r.URL.Path)
+		assert.Equal(t, "metric_sets", r.URL.Query().Get("include"))
+		fmt.Fprint(w, `{"tickets":[{"id":7,"subject":"Printer","status":"open"}],"metric_sets":[{"ticket_id":7,"replies":2}]}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"ticketMetrics","includeTickets":true}`)})
+	require.NoError(t, resp.Error)
+	subject, _ := resp.Frames[0].FieldByName("subject")
+	assert.Equal(t, "Printer", subject.At(0))
+}
+
+func TestQueryTicketMetrics_TicketIDSideloadsTicket(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets/show_many.json", r.URL.Path)
+		assert.Equal(t, "7", r.URL.Query().Get("ids"))
+		assert.Equal(t, "metric_sets", r.URL.Query().Get("include"))
+		fmt.Fprint(w, `{"tickets":[{"id":7,"subject":"Printer","status":"open"}],"metric_sets":[{"ticket_id":7,"replies":2}]}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"ticketMetrics","ticketId":7,"includeTickets":true}`)})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	require.Equal(t, 1, frame.Rows())
+	replies, _ := frame.FieldByName("replies")
+	assert.Equal(t, int64(2), replies.At(0))
+	subject, _ := frame.FieldByName("subject")
+	assert.Equal(t, "Printer", subject.At(0))
+	status, _ := frame.FieldByName("status")
+	assert.Equal(t, "open", status.At(0))
+}
+
+func TestQueryTicketMetrics_TimeRange(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/search.json":
+			assert.Equal(t, "type:ticket solved>2024-01-01T00:00:00Z solved<2024-01-02T00:00:00Z", r.URL.Query().Get("query"))
+			fmt.Fprint(w, `{"results":[{"id":7,"result_type":"ticket"},{"id":8,"result_type":"ticket"}],"count":2}`)
+		case "/api/v2/tickets/show_many.json":
+			assert.Equal(t, "7,8", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"tickets":[{"id":7,"subject":"Printer","status":"solved"},{"id":8,"subject":"Reopened","status":"open"}],
+				"metric_sets":[{"ticket_id":7,"solved_at":"2024-01-01T10:00:00Z"},{"ticket_id":8}]}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"ticketMetrics","includeTickets":true,"timeField":"solved_at"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(24 * time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	// Ticket 8 is no longer solved, so its metrics and ticket are dropped
+	require.Equal(t, 1, frame.Rows())
+	subject, _ := frame.FieldByName("subject")
+	assert.Equal(t, "Printer", subject.At(0))
+}
@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"math"
+	"sort"
+)
+
+// percentile returns the p-th percentile (0-100) of values using linear
+// interpolation between closest ranks; values is sorted in place
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sort.Float64s(values)
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return values[0]
+	}
+	if upper >= len(values) {
+		return values[len(values)-1]
+	}
+	return values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+}
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
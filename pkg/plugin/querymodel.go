@@ -0,0 +1,403 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// queryModelVersion is the schema version of the query model. Saved queries
+// of older versions are migrated before they are decoded.
+const queryModelVersion = 1
+
+// formatTable is the default query format returning records as rows
+const formatTable = "table"
+
+// Advanced filter operators
+const (
+	operatorEquals      = "equals"
+	operatorNotEquals   = "not_equals"
+	operatorContains    = "contains"
+	operatorGreaterThan = "greater_than"
+	operatorLessThan    = "less_than"
+	operatorStartsWith  = "starts_with"
+	operatorEndsWith    = "ends_with"
+)
+
+// Advanced filter logic joining a filter to the filters before it
+const (
+	logicAnd = "AND"
+	logicOr  = "OR"
+)
+
+var (
+	ticketStatuses   = []string{"new", "open", "pending", "hold", "solved", "closed"}
+	ticketPriorities = []string{"low", "normal", "high", "urgent"}
+	filterOperators  = []string{operatorEquals, operatorNotEquals, operatorContains, operatorGreaterThan, operatorLessThan, operatorStartsWith, operatorEndsWith}
+)
+
+// AdvancedFilter is a condition on a record field set in the query editor
+type AdvancedFilter struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+	// Logic joins the filter to the filters before it, AND by default
+	Logic string `json:"logic,omitempty"`
+}
+
+// QueryModel is the query JSON saved by the query editor
+type QueryModel struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	QueryType     string `json:"queryType"`
+	Format        string `json:"format,omitempty"`
+
+	// Record selection
+	TicketID     int64         `json:"ticketId,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Priority     string        `json:"priority,omitempty"`
+	AssigneeID   int64         `json:"assigneeId,omitempty"`
+	RequesterID  int64         `json:"requesterId,omitempty"`
+	Query        string        `json:"query,omitempty"`
+	SearchType   string        `json:"searchType,omitempty"`
+	ViewID       int64         `json:"viewId,omitempty"`
+	ViewIDs      []int64       `json:"viewIds,omitempty"`
+	TimeField    string        `json:"timeField,omitempty"`
+	CustomFields []interface{} `json:"customFields,omitempty"`
+
+	// Options of single query types
+	IncludeDeleted     bool   `json:"includeDeleted,omitempty"`
+	IncludeTickets     bool   `json:"includeTickets,omitempty"`
+	IncludeMemberships bool   `json:"includeMemberships,omitempty"`
+	Score              string `json:"score,omitempty"`
+	Breakdown          string `json:"breakdown,omitempty"`
+
+	// Table output
+	Fields          []string         `json:"fields,omitempty"`
+	Limit           int              `json:"limit,omitempty"`
+	AdvancedFilters []AdvancedFilter `json:"advancedFilters,omitempty"`
+
+	// Time series and group-by output
+	Interval     string      `json:"interval,omitempty"`
+	Aggregation  string      `json:"aggregation,omitempty"`
+	ValueField   string      `json:"valueField,omitempty"`
+	Percentile   *float64    `json:"percentile,omitempty"`
+	SeriesBy     string      `json:"seriesBy,omitempty"`
+	FrameType    string      `json:"frameType,omitempty"`
+	GroupBy      string      `json:"groupBy,omitempty"`
+	GroupByField interface{} `json:"groupByField,omitempty"`
+	TopN         int         `json:"topN,omitempty"`
+}
+
+// parseQueryModel migrates the JSON of a query to the current schema
+// version and decodes it into a validated model
+func parseQueryModel(raw []byte) (*QueryModel, error) {
+	var queryModel map[string]interface{}
+	if err := json.Unmarshal(raw, &queryModel); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal query: %v", err)
+	}
+	if err := migrateQueryModel(queryModel); err != nil {
+		return nil, err
+	}
+
+	migrated, err := json.Marshal(queryModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %v", err)
+	}
+	model := &QueryModel{}
+	if err := json.Unmarshal(migrated, model); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%s must be %s, got %s", jsonFieldPath(typeErr.Field), jsonTypeName(typeErr.Type), typeErr.Value)
+		}
+		return nil, fmt.Errorf("failed to unmarshal query: %v", err)
+	}
+	if err := model.validate(); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// jsonFieldPath formats a decoding error path such as "fields.0" as "fields[0]"
+func jsonFieldPath(path string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(path, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			fmt.Fprintf(&b, "[%s]", part)
+			continue
+		}
+		if i > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// jsonTypeName names a Go type the way the query editor sees it
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return "a " + t.String()
+}
+
+// queryModelMigrations upgrade the JSON of a saved query one schema version
+// at a time; the migration at index i upgrades version i to i+1
+var queryModelMigrations = []func(queryModel map[string]interface{}){
+	migrateQueryModelV0,
+}
+
+// migrateQueryModel upgrades the JSON of a saved query to queryModelVersion.
+// Queries saved before versioning have no schemaVersion and are version 0.
+func migrateQueryModel(queryModel map[string]interface{}) error {
+	version := 0
+	if v, ok := queryModel["schemaVersion"].(float64); ok {
+		version = int(v)
+	}
+	if version > queryModelVersion {
+		return fmt.Errorf("query schema version %d is newer than the supported version %d", version, queryModelVersion)
+	}
+	for ; version < queryModelVersion; version++ {
+		queryModelMigrations[version](queryModel)
+	}
+	queryModel["schemaVersion"] = float64(queryModelVersion)
+	return nil
+}
+
+// migrateQueryModelV0 upgrades unversioned queries. Their editor saved ids
+// and limits typed into text inputs as strings, fields as a comma separated
+// string and status, priority and filter logic in any case.
+func migrateQueryModelV0(queryModel map[string]interface{}) {
+	for _, key := range []string{"ticketId", "assigneeId", "requesterId", "viewId", "limit", "topN", "percentile"} {
+		if s, ok := queryModel[key].(string); ok {
+			if s = strings.TrimSpace(s); s == "" {
+				delete(queryModel, key)
+			} else if n, err := strconv.ParseFloat(s, 64); err == nil {
+				queryModel[key] = n
+			}
+		}
+	}
+	if ids, ok := queryModel["viewIds"].([]interface{}); ok {
+		for i, id := range ids {
+			if s, ok := id.(string); ok {
+				if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+					ids[i] = n
+				}
+			}
+		}
+	}
+	if fields, ok := queryModel["fields"].(string); ok {
+		var names []interface{}
+		for _, name := range strings.Split(fields, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		queryModel["fields"] = names
+	}
+	for _, key := range []string{"status", "priority"} {
+		if s, ok := queryModel[key].(string); ok {
+			queryModel[key] = strings.ToLower(s)
+		}
+	}
+	if filters, ok := queryModel["advancedFilters"].([]interface{}); ok {
+		for _, f := range filters {
+			if filter, ok := f.(map[string]interface{}); ok {
+				if logic, ok := filter["logic"].(string); ok {
+					filter["logic"] = strings.ToUpper(logic)
+				}
+			}
+		}
+	}
+}
+
+// validate reports the first invalid option of the query
+func (m *QueryModel) validate() error {
+	if m.QueryType == "" {
+		return fmt.Errorf("queryType is required")
+	}
+
+	// Options required by a query type
+	switch m.QueryType {
+	case "ticketById":
+		if m.TicketID <= 0 {
+			return fmt.Errorf("ticketId is required for ticketById queries")
+		}
+	case "search":
+		if strings.TrimSpace(m.Query) == "" {
+			return fmt.Errorf("query is required for search queries")
+		}
+	case "view":
+		if m.ViewID <= 0 && len(m.ViewIDs) == 0 {
+			return fmt.Errorf("viewId or viewIds is required for view queries")
+		}
+	}
+
+	switch m.Format {
+	case "", formatTable, formatTimeSeries:
+	default:
+		return fmt.Errorf("unknown format: %s", m.Format)
+	}
+	if m.Status != "" && !containsString(ticketStatuses, m.Status) {
+		return fmt.Errorf("unknown status: %s", m.Status)
+	}
+	if m.Priority != "" && !containsString(ticketPriorities, m.Priority) {
+		return fmt.Errorf("unknown priority: %s", m.Priority)
+	}
+	if m.Score != "" && !containsString(csatScores, m.Score) {
+		return fmt.Errorf("unknown csat score: %s", m.Score)
+	}
+	if _, ok := csatBreakdownLabels[m.Breakdown]; m.Breakdown != "" && !ok {
+		return fmt.Errorf("unsupported csat breakdown: %s", m.Breakdown)
+	}
+	if m.AssigneeID < 0 {
+		return fmt.Errorf("assigneeId must be a positive id")
+	}
+	if m.RequesterID < 0 {
+		return fmt.Errorf("requesterId must be a positive id")
+	}
+	if m.Limit < 0 || m.Limit > maxQueryRows {
+		return fmt.Errorf("limit must be between 0 and %d", maxQueryRows)
+	}
+	for _, field := range m.CustomFields {
+		if !isFieldRef(field) {
+			return fmt.Errorf("customFields must hold field ids or titles, got %v", field)
+		}
+	}
+	if m.GroupByField != nil && !isFieldRef(m.GroupByField) {
+		return fmt.Errorf("groupByField must be a field id or title, got %v", m.GroupByField)
+	}
+	for i, filter := range m.AdvancedFilters {
+		if err := filter.validate(); err != nil {
+			return fmt.Errorf("advancedFilters[%d]: %w", i, err)
+		}
+		// Custom ticket fields are resolved once the ticket fields are fetched
+		if columns, custom := filterColumns(m); columns != nil && !custom && !containsString(columns, filter.Field) {
+			return fmt.Errorf("advancedFilters[%d]: unknown filter field: %s", i, filter.Field)
+		}
+	}
+	return nil
+}
+
+// validate reports an incomplete or unknown filter condition
+func (f *AdvancedFilter) validate() error {
+	if f.Field == "" {
+		return fmt.Errorf("field is required")
+	}
+	if !containsString(filterOperators, f.Operator) {
+		return fmt.Errorf("unknown operator: %s", f.Operator)
+	}
+	switch f.Value.(type) {
+	case string, float64, bool:
+	case nil:
+		return fmt.Errorf("value is required")
+	default:
+		return fmt.Errorf("value must be a string, number or boolean")
+	}
+	switch f.Logic {
+	case "", logicAnd, logicOr:
+	default:
+		return fmt.Errorf("unknown logic: %s", f.Logic)
+	}
+	return nil
+}
+
+// isFieldRef reports whether v names a custom field by id or title
+func isFieldRef(v interface{}) bool {
+	switch v.(type) {
+	case float64, string:
+		return true
+	}
+	return false
+}
+
+// carryNotices appends the notices of the record frames to frame, which
+// replaces them in the response
+func carryNotices(frame *data.Frame, records data.Frames) {
+	for _, record := range records {
+		if record.Meta != nil && len(record.Meta.Notices) > 0 {
+			frame.AppendNotices(record.Meta.Notices...)
+		}
+	}
+}
+
+// containsString reports whether values holds s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// tableResponse applies the fields and limit of a table query to the record
+// frames of resp
+func tableResponse(model *QueryModel, resp *backend.DataResponse) *backend.DataResponse {
+	if len(model.Fields) == 0 && model.Limit == 0 {
+		return resp
+	}
+
+	known := map[string]bool{}
+	for _, frame := range resp.Frames {
+		for _, field := range frame.Fields {
+			known[field.Name] = true
+		}
+	}
+	for _, name := range model.Fields {
+		if !known[name] {
+			return &backend.DataResponse{
+				Error:  fmt.Errorf("unknown field: %s", name),
+				Status: backend.StatusBadRequest,
+			}
+		}
+	}
+
+	frames := make(data.Frames, 0, len(resp.Frames))
+	for _, frame := range resp.Frames {
+		frames = append(frames, selectFrame(frame, model.Fields, model.Limit))
+	}
+	return &backend.DataResponse{
+		Frames: frames,
+	}
+}
+
+// selectFrame returns the named fields of frame, in order, limited to the
+// first limit rows. No names keep every field and a zero limit every row.
+func selectFrame(frame *data.Frame, names []string, limit int) *data.Frame {
+	selected := frame
+	if len(names) > 0 {
+		selected = data.NewFrame(frame.Name)
+		selected.Meta = frame.Meta
+		for _, name := range names {
+			if field, _ := frame.FieldByName(name); field != nil {
+				selected.Fields = append(selected.Fields, field)
+			}
+		}
+	}
+	if limit == 0 || selected.Rows() <= limit {
+		return selected
+	}
+
+	limited := selected.EmptyCopy()
+	limited.Meta = selected.Meta
+	for i := 0; i < limit; i++ {
+		limited.AppendRow(selected.RowCopy(i)...)
+	}
+	return limited
+}
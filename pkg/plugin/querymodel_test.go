@@ -0,0 +1,103 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueryModel_MigratesUnversionedQueries(t *testing.T) {
+	model, err := parseQueryModel([]byte(`{
+		"queryType":"tickets","status":"Open","assigneeId":"7","limit":" 50 ","requesterId":"",
+		"fields":"id, subject,,status","viewIds":["1",2],
+		"advancedFilters":[{"field":"tags","operator":"contains","value":"vip","logic":"or"}]}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, queryModelVersion, model.SchemaVersion)
+	assert.Equal(t, "open", model.Status)
+	assert.Equal(t, int64(7), model.AssigneeID)
+	assert.Equal(t, int64(0), model.RequesterID)
+	assert.Equal(t, 50, model.Limit)
+	assert.Equal(t, []string{"id", "subject", "status"}, model.Fields)
+	assert.Equal(t, []int64{1, 2}, model.ViewIDs)
+	require.Len(t, model.AdvancedFilters, 1)
+	assert.Equal(t, logicOr, model.AdvancedFilters[0].Logic)
+
+	// Query types read the migrated options
+	assert.Equal(t, map[string]string{"status": "open", "assignee": "7"}, ticketListParams(model))
+}
+
+func TestParseQueryModel_CurrentVersionIsNotMigrated(t *testing.T) {
+	model, err := parseQueryModel([]byte(`{"schemaVersion":1,"queryType":"tickets","fields":["id"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id"}, model.Fields)
+
+	_, err = parseQueryModel([]byte(`{"schemaVersion":1,"queryType":"tickets","assigneeId":"7"}`))
+	assert.EqualError(t, err, "assigneeId must be an integer, got string")
+}
+
+func TestParseQueryModel_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		json string
+		err  string
+	}{
+		{`{}`, "queryType is required"},
+		{`{"queryType":"tickets","assigneeId":true}`, "assigneeId must be an integer, got bool"},
+		{`{"queryType":"tickets","fields":[1]}`, "fields[0] must be a string, got number"},
+		{`{"queryType":"tickets","advancedFilters":[{"field":1}]}`, "advancedFilters[0].field must be a string, got number"},
+		{`{"queryType":"ticketById"}`, "ticketId is required for ticketById queries"},
+		{`{"queryType":"search","query":" "}`, "query is required for search queries"},
+		{`{"queryType":"view"}`, "viewId or viewIds is required for view queries"},
+		{`{"queryType":"tickets","status":"archived"}`, "unknown status: archived"},
+		{`{"queryType":"tickets","format":"graph"}`, "unknown format: graph"},
+		{`{"queryType":"tickets","limit":20000}`, "limit must be between 0 and 10000"},
+		{`{"queryType":"tickets","customFields":[true]}`, "customFields must hold field ids or titles, got true"},
+		{`{"queryType":"tickets","advancedFilters":[{"field":"status","operator":"like","value":"open"}]}`, "advancedFilters[0]: unknown operator: like"},
+		{`{"queryType":"tickets","advancedFilters":[{"field":"status","operator":"equals"}]}`, "advancedFilters[0]: value is required"},
+		{`{"schemaVersion":2,"queryType":"tickets"}`, "query schema version 2 is newer than the supported version 1"},
+	} {
+		_, err := parseQueryModel([]byte(tc.json))
+		assert.EqualError(t, err, tc.err, tc.json)
+	}
+}
+
+func TestQueryTickets_FieldsAndLimit(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tickets":[
+			{"id":1,"subject":"a","status":"open"},
+			{"id":2,"subject":"b","status":"open"},
+			{"id":3,"subject":"c","status":"open"}],
+			"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","fields":["subject","id"],"limit":2}`)})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	require.Len(t, frame.Fields, 2)
+	assert.Equal(t, "subject", frame.Fields[0].Name)
+	assert.Equal(t, "id", frame.Fields[1].Name)
+	assert.Equal(t, 2, frame.Rows())
+
+	resp = ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","fields":["mood"]}`)})
+	assert.EqualError(t, resp.Error, "unknown field: mood")
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
+
+func TestQueryTickets_AssigneeAndRequester(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/search.json", r.URL.Path)
+		assert.Equal(t, "type:ticket status:open assignee:7 requester:9", r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"results":[{"id":1,"result_type":"ticket","status":"open","assignee_id":7,"requester_id":9}],
+			"users":[{"id":7,"name":"Agent"},{"id":9,"name":"Customer"}],"count":1}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","status":"open","assigneeId":7,"requesterId":9}`)})
+	require.NoError(t, resp.Error)
+	id, _ := resp.Frames[0].FieldByName("id")
+	require.Equal(t, 1, id.Len())
+}
@@ -0,0 +1,108 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// querySearch handles search queries using Zendesk search syntax. The
+// dashboard time range and the advanced filters search can apply are
+// appended to the query as search terms.
+func (ds *Datasource) querySearch(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	searchQuery := model.Query
+	if searchQuery == "" {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("search query is required"),
+		}
+	}
+
+	// Searches return tickets unless another result type is requested;
+	// "all" keeps mixed results and is limited to the first 1000 matches
+	resultType := zendesk.SearchTypeTicket
+	if model.SearchType != "" {
+		resultType = model.SearchType
+	}
+	switch resultType {
+	case zendesk.SearchTypeTicket, zendesk.SearchTypeUser, zendesk.SearchTypeOrganization:
+	case "all":
+		resultType = ""
+	default:
+		return &backend.DataResponse{
+			Error: fmt.Errorf("unknown search type: %s", resultType),
+		}
+	}
+
+	// Only tickets have a solved time
+	timeFields := []string{timeFieldCreatedAt, timeFieldUpdatedAt}
+	if resultType == zendesk.SearchTypeTicket {
+		timeFields = append(timeFields, timeFieldSolvedAt)
+	}
+	filter, err := newTimeFilter(query, model, timeFields...)
+	if err != nil {
+		return timeFilterError(err)
+	}
+	if terms := filterSearchTerms(model, resultType); terms != "" {
+		searchQuery += " " + terms
+	}
+	searchQuery = filter.withSearchTerms(searchQuery)
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("search:%s:%s:%s", resultType, searchQuery, filter.key())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if results, ok := cached.(*zendesk.SearchResults); ok {
+			return ds.searchResponse(ctx, results, resultType, model)
+		}
+	}
+
+	// Fetch from API
+	results, err := ds.zendeskClient.Search(ctx, searchQuery, resultType, maxQueryRows, ticketIncludes...)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to search: %w", err),
+		}
+	}
+
+	results.Tickets = filter.tickets(results.Tickets)
+	results.Users = filter.users(results.Users)
+	results.Organizations = filter.organizations(results.Organizations)
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, results, cache.DefaultConfig().DefaultTTL)
+
+	return ds.searchResponse(ctx, results, resultType, model)
+}
+
+// searchResponse converts search results to frames with the ticket lookups of the query
+func (ds *Datasource) searchResponse(ctx context.Context, results *zendesk.SearchResults, resultType string, model *QueryModel) *backend.DataResponse {
+	lookups, err := ds.ticketLookups(ctx, results.Tickets, &results.Sideloads, model)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: err,
+		}
+	}
+	return ds.searchResultsToDataFrames(results, resultType, lookups)
+}
+
+// searchResultsToDataFrames converts search results to one frame per result type
+func (ds *Datasource) searchResultsToDataFrames(results *zendesk.SearchResults, resultType string, lookups *ticketLookups) *backend.DataResponse {
+	frames := data.Frames{}
+	if resultType == "" || resultType == zendesk.SearchTypeTicket {
+		frames = append(frames, ds.ticketsToDataFrame(results.Tickets, lookups).Frames...)
+	}
+	if resultType == "" || resultType == zendesk.SearchTypeUser {
+		frames = append(frames, ds.usersToDataFrame(results.Users).Frames...)
+	}
+	if resultType == "" || resultType == zendesk.SearchTypeOrganization {
+		frames = append(frames, ds.organizationsToDataFrame(results.Organizations).Frames...)
+	}
+
+	return &backend.DataResponse{
+		Frames: frames,
+	}
+}
@@ -0,0 +1,285 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// SLA states reported by the slaStatus query
+const (
+	slaActive    = "active"
+	slaPaused    = "paused"
+	slaFulfilled = "fulfilled"
+	slaBreached  = "breached"
+)
+
+// slaKey identifies one SLA metric instance of a ticket
+type slaKey struct {
+	TicketID   int64
+	Metric     string
+	InstanceID int64
+}
+
+// slaState is the reconstructed state of one SLA metric instance
+type slaState struct {
+	slaKey
+	PolicyID      int64
+	Policy        string
+	TargetMinutes int64
+	BusinessHours bool
+	Status        string
+	BreachAt      *time.Time
+	FulfilledAt   *time.Time
+	// RemainingMinutes is set for active instances with a scheduled breach
+	RemainingMinutes *float64
+}
+
+// slaPriorNotice tells how many SLA instances were applied before the time range
+const slaPriorNotice = "%d SLA instances were applied before the time range starts and are not shown; widen the time range to include them."
+
+// slaStatusResult holds the inputs of the slaStatus frames
+type slaStatusResult struct {
+	Events   []zendesk.TicketMetricEvent
+	Policies []zendesk.SLAPolicy
+	// Capped is set when the export stopped at maxQueryRows events
+	Capped bool
+}
+
+// reconstructSLAStates replays ticket metric events up to now and evaluates
+// every SLA instance at now. Only metrics an SLA policy was applied to are
+// reported.
+func reconstructSLAStates(events []zendesk.TicketMetricEvent, policies []zendesk.SLAPolicy, now time.Time) []*slaState {
+	titles := make(map[int64]string, len(policies))
+	for _, policy := range policies {
+		titles[policy.ID] = policy.Title
+	}
+
+	sorted := make([]zendesk.TicketMetricEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	states := map[slaKey]*slaState{}
+	for _, event := range sorted {
+		at := event.Time
+		// Events after now had not happened yet; breach events carry the
+		// scheduled breach time and are kept
+		if at.After(now) && event.Type != zendesk.MetricEventBreach {
+			continue
+		}
+		key := slaKey{TicketID: event.TicketID, Metric: event.Metric, InstanceID: event.InstanceID}
+		state, applied := states[key]
+
+		if event.Type == zendesk.MetricEventApplySLA && event.SLA != nil {
+			if !applied {
+				state = &slaState{slaKey: key, Status: slaActive}
+				states[key] = state
+			}
+			state.PolicyID = event.SLA.Policy.ID
+			state.Policy = event.SLA.Policy.Title
+			if title, ok := titles[state.PolicyID]; ok && title != "" {
+				state.Policy = title
+			}
+			state.TargetMinutes = event.SLA.Target
+			state.BusinessHours = event.SLA.BusinessHours
+			continue
+		}
+		if !applied {
+			continue
+		}
+
+		switch event.Type {
+		case zendesk.MetricEventActivate:
+			if state.FulfilledAt == nil {
+				state.Status = slaActive
+			}
+		case zendesk.MetricEventPause:
+			if state.FulfilledAt == nil {
+				state.Status = slaPaused
+			}
+		case zendesk.MetricEventFulfill:
+			state.Status = slaFulfilled
+			fulfilledAt := at
+			state.FulfilledAt = &fulfilledAt
+		case zendesk.MetricEventBreach:
+			// Deleted breach events were cancelled, e.g. when the SLA was removed
+			if event.Deleted {
+				state.BreachAt = nil
+			} else {
+				breachAt := at
+				state.BreachAt = &breachAt
+			}
+		}
+	}
+
+	result := make([]*slaState, 0, len(states))
+	for _, state := range states {
+		switch {
+		case state.FulfilledAt != nil:
+			state.Status = slaFulfilled
+			if state.BreachAt != nil && state.BreachAt.Before(*state.FulfilledAt) {
+				state.Status = slaBreached
+			}
+		case state.BreachAt != nil && !state.BreachAt.After(now):
+			state.Status = slaBreached
+		case state.BreachAt != nil && state.Status == slaActive:
+			remaining := state.BreachAt.Sub(now).Minutes()
+			state.RemainingMinutes = &remaining
+		}
+		result = append(result, state)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.TicketID != b.TicketID {
+			return a.TicketID < b.TicketID
+		}
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		return a.InstanceID < b.InstanceID
+	})
+	return result
+}
+
+// querySLAStatus handles SLA status queries over the query time range.
+// SLA instances applied before the range starts are not reconstructed; the
+// table notes how many were seen.
+func (ds *Datasource) querySLAStatus(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	opts := zendesk.IncrementalOptions{
+		StartTime: query.TimeRange.From,
+		EndTime:   query.TimeRange.To,
+		MaxRows:   maxQueryRows,
+	}
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("slaStatus:%d:%d", query.TimeRange.From.Unix(), query.TimeRange.To.Unix())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if result, ok := cached.(*slaStatusResult); ok {
+			return ds.slaStatusToDataFrames(result, query)
+		}
+	}
+
+	// Fetch from API
+	policies, err := ds.zendeskClient.ListSLAPolicies(ctx)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch SLA policies: %w", err),
+		}
+	}
+
+	result := &slaStatusResult{Policies: policies}
+	_, err = ds.zendeskClient.IncrementalTicketMetricEvents(ctx, opts, func(page []zendesk.TicketMetricEvent) error {
+		result.Events = append(result.Events, page...)
+		return nil
+	})
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch ticket metric events: %w", err),
+		}
+	}
+	result.Capped = len(result.Events) >= maxQueryRows
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, result, cache.DefaultConfig().DefaultTTL)
+
+	return ds.slaStatusToDataFrames(result, query)
+}
+
+// slaPriorInstances counts the SLA instances with a breach among events but
+// no apply_sla event, i.e. those applied before the exported time range
+func slaPriorInstances(events []zendesk.TicketMetricEvent) int {
+	applied := map[slaKey]bool{}
+	breached := map[slaKey]bool{}
+	for _, event := range events {
+		key := slaKey{TicketID: event.TicketID, Metric: event.Metric, InstanceID: event.InstanceID}
+		switch event.Type {
+		case zendesk.MetricEventApplySLA:
+			applied[key] = true
+		case zendesk.MetricEventBreach:
+			breached[key] = true
+		}
+	}
+	count := 0
+	for key := range breached {
+		if !applied[key] {
+			count++
+		}
+	}
+	return count
+}
+
+// slaStatusToDataFrames converts SLA states to a table frame and a
+// breach-count time series bucketed by the query interval
+func (ds *Datasource) slaStatusToDataFrames(result *slaStatusResult, query backend.DataQuery) *backend.DataResponse {
+	now := time.Now()
+	if !query.TimeRange.To.IsZero() && query.TimeRange.To.Before(now) {
+		now = query.TimeRange.To
+	}
+	states := reconstructSLAStates(result.Events, result.Policies, now)
+
+	table := data.NewFrame("sla_status")
+	table.Fields = append(table.Fields,
+		data.NewField("ticket_id", nil, []int64{}),
+		data.NewField("metric", nil, []string{}),
+		data.NewField("policy", nil, []string{}),
+		data.NewField("target_minutes", nil, []int64{}),
+		data.NewField("business_hours", nil, []bool{}),
+		data.NewField("status", nil, []string{}),
+		data.NewField("breach_at", nil, []*time.Time{}),
+		data.NewField("fulfilled_at", nil, []*time.Time{}),
+		data.NewField("time_remaining_minutes", nil, []*float64{}),
+	)
+	for _, state := range states {
+		table.AppendRow(
+			state.TicketID,
+			state.Metric,
+			state.Policy,
+			state.TargetMinutes,
+			state.BusinessHours,
+			state.Status,
+			state.BreachAt,
+			state.FulfilledAt,
+			state.RemainingMinutes,
+		)
+	}
+	if result.Capped {
+		table.AppendNotices(data.Notice{Severity: data.NoticeSeverityWarning, Text: incrementalRowCapNotice})
+	}
+	if prior := slaPriorInstances(result.Events); prior > 0 {
+		table.AppendNotices(data.Notice{Severity: data.NoticeSeverityInfo, Text: fmt.Sprintf(slaPriorNotice, prior)})
+	}
+
+	interval := query.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	breaches := data.NewFrame("", data.NewField("breach_at", nil, []time.Time{}))
+	for _, state := range states {
+		if state.Status == slaBreached && state.BreachAt != nil {
+			breaches.AppendRow(*state.BreachAt)
+		}
+	}
+	times, counts, err := bucketSums(breaches, "breach_at", "", query.TimeRange, interval)
+	if err != nil {
+		return &backend.DataResponse{
+			Error:  err,
+			Status: backend.StatusBadRequest,
+		}
+	}
+	series := data.NewFrame("sla_breaches",
+		data.NewField("time", nil, times),
+		data.NewField("breaches", nil, counts),
+	)
+
+	return &backend.DataResponse{
+		Frames: data.Frames{table, series},
+	}
+}
@@ -0,0 +1,164 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// slaTime returns a time on the day of the SLA test events
+func slaTime(hour, minute int) time.Time {
+	return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+}
+
+// slaEvents applies a reply time SLA to tickets 1-3: ticket 1 is fulfilled in
+// time, ticket 2 breaches and ticket 3 is still running
+func slaEvents() []zendesk.TicketMetricEvent {
+	apply := func(ticketID int64) zendesk.TicketMetricEvent {
+		return zendesk.TicketMetricEvent{
+			TicketID: ticketID, Metric: "reply_time", InstanceID: 1, Type: zendesk.MetricEventApplySLA,
+			Time: slaTime(8, 0),
+			SLA:  &zendesk.SLAEventDetails{Target: 60, Policy: zendesk.SLAPolicyRef{ID: 9, Title: "old title"}},
+		}
+	}
+	event := func(ticketID int64, eventType string, at time.Time) zendesk.TicketMetricEvent {
+		return zendesk.TicketMetricEvent{TicketID: ticketID, Metric: "reply_time", InstanceID: 1, Type: eventType, Time: at}
+	}
+	return []zendesk.TicketMetricEvent{
+		apply(1), event(1, zendesk.MetricEventBreach, slaTime(9, 0)), event(1, zendesk.MetricEventFulfill, slaTime(8, 30)),
+		apply(2), event(2, zendesk.MetricEventBreach, slaTime(9, 0)),
+		apply(3), event(3, zendesk.MetricEventBreach, slaTime(11, 0)),
+		// Metrics without an applied SLA are ignored
+		event(4, zendesk.MetricEventActivate, slaTime(8, 0)),
+	}
+}
+
+func TestReconstructSLAStates(t *testing.T) {
+	policies := []zendesk.SLAPolicy{{ID: 9, Title: "Gold"}}
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	states := reconstructSLAStates(slaEvents(), policies, now)
+	require.Len(t, states, 3)
+
+	assert.Equal(t, slaFulfilled, states[0].Status)
+	assert.Equal(t, "Gold", states[0].Policy)
+	assert.Equal(t, slaBreached, states[1].Status)
+	assert.Equal(t, slaActive, states[2].Status)
+	require.NotNil(t, states[2].RemainingMinutes)
+	assert.Equal(t, 60.0, *states[2].RemainingMinutes)
+}
+
+func TestReconstructSLAStates_DeletedBreach(t *testing.T) {
+	events := slaEvents()[3:5]
+	events = append(events, zendesk.TicketMetricEvent{
+		TicketID: 2, Metric: "reply_time", InstanceID: 1, Type: zendesk.MetricEventBreach,
+		Time: slaTime(9, 0), Deleted: true,
+	})
+
+	states := reconstructSLAStates(events, nil, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	require.Len(t, states, 1)
+	assert.Equal(t, slaActive, states[0].Status)
+}
+
+func TestReconstructSLAStates_EventsAfterNow(t *testing.T) {
+	// Ticket 3 breaches at 11:00 but is fulfilled at 10:30, after now
+	events := slaEvents()[5:7]
+	events = append(events, zendesk.TicketMetricEvent{
+		TicketID: 3, Metric: "reply_time", InstanceID: 1, Type: zendesk.MetricEventFulfill,
+		Time: slaTime(10, 30),
+	})
+
+	states := reconstructSLAStates(events, nil, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	require.Len(t, states, 1)
+	assert.Equal(t, slaActive, states[0].Status)
+	assert.Nil(t, states[0].FulfilledAt)
+	require.NotNil(t, states[0].RemainingMinutes)
+	assert.Equal(t, 60.0, *states[0].RemainingMinutes)
+
+	// Once the range covers the fulfillment, it counts
+	states = reconstructSLAStates(events, nil, time.Date(2024, 1, 1, 11, 30, 0, 0, time.UTC))
+	require.Len(t, states, 1)
+	assert.Equal(t, slaFulfilled, states[0].Status)
+}
+
+func TestSLAStatusToDataFrames(t *testing.T) {
+	ds := &Datasource{}
+	query := backend.DataQuery{
+		Interval: time.Hour,
+		TimeRange: backend.TimeRange{
+			From: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	resp := ds.slaStatusToDataFrames(&slaStatusResult{Events: slaEvents()}, query)
+	require.Len(t, resp.Frames, 2)
+	assert.Equal(t, 3, resp.Frames[0].Rows())
+
+	series := resp.Frames[1]
+	require.Equal(t, 3, series.Rows())
+	assert.Equal(t, []interface{}{time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), int64(1)}, []interface{}{series.Fields[0].At(1), series.Fields[1].At(1)})
+}
+
+func TestSLAStatusToDataFrames_Notices(t *testing.T) {
+	ds := &Datasource{}
+	query := backend.DataQuery{
+		TimeRange: backend.TimeRange{
+			From: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	// Ticket 5 breaches in the range, its SLA was applied before it
+	events := append(slaEvents(), zendesk.TicketMetricEvent{
+		TicketID: 5, Metric: "reply_time", InstanceID: 1, Type: zendesk.MetricEventBreach, Time: slaTime(9, 0),
+	})
+
+	resp := ds.slaStatusToDataFrames(&slaStatusResult{Events: events, Capped: true}, query)
+	table := resp.Frames[0]
+	assert.Equal(t, 3, table.Rows())
+	require.NotNil(t, table.Meta)
+	require.Len(t, table.Meta.Notices, 2)
+	assert.Equal(t, incrementalRowCapNotice, table.Meta.Notices[0].Text)
+	assert.Equal(t, fmt.Sprintf(slaPriorNotice, 1), table.Meta.Notices[1].Text)
+	assert.Nil(t, resp.Frames[1].Meta)
+}
+
+func TestQuerySLAStatus_EndTime(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/slas/policies.json":
+			fmt.Fprint(w, `{"sla_policies":[]}`)
+		case "/api/v2/incremental/ticket_metric_events.json":
+			// The breach scheduled after the range is kept, the fulfillment after it ends the export
+			fmt.Fprintf(w, `{"ticket_metric_events":[
+				{"ticket_id":1,"metric":"reply_time","instance_id":1,"type":"apply_sla","time":"2024-01-01T08:00:00Z","sla":{"target":180}},
+				{"ticket_id":1,"metric":"reply_time","instance_id":1,"type":"breach","time":"2024-01-01T11:00:00Z"},
+				{"ticket_id":1,"metric":"reply_time","instance_id":1,"type":"fulfill","time":"2024-01-01T10:30:00Z"}],
+				"next_page":"http://%s/api/v2/incremental/ticket_metric_events.json?start_time=1704105000","end_of_stream":false}`, r.Host)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON: []byte(`{"queryType":"slaStatus"}`),
+		TimeRange: backend.TimeRange{
+			From: time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		},
+	})
+	require.NoError(t, resp.Error)
+	table := resp.Frames[0]
+	require.Equal(t, 1, table.Rows())
+	status, _ := table.FieldByName("status")
+	assert.Equal(t, slaActive, status.At(0))
+	assert.Nil(t, table.Meta)
+}
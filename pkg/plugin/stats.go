@@ -0,0 +1,270 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// orderedCounts counts keys in the order they were first seen, matching the
+// insertion order of the frontend's Record counters
+type orderedCounts struct {
+	Keys   []string
+	Counts map[string]int
+}
+
+// add counts one occurrence of key
+func (c *orderedCounts) add(key string) {
+	if c.Counts == nil {
+		c.Counts = map[string]int{}
+	}
+	if _, ok := c.Counts[key]; !ok {
+		c.Keys = append(c.Keys, key)
+	}
+	c.Counts[key]++
+}
+
+// appendRows appends one "<prefix>: <key>" row per key to a stats frame
+func (c *orderedCounts) appendRows(frame *data.Frame, prefix string) {
+	for _, key := range c.Keys {
+		frame.AppendRow(fmt.Sprintf("%s: %s", prefix, key), float64(c.Counts[key]))
+	}
+}
+
+// newStatsFrame returns an empty Metric/Value frame, the schema of the
+// frontend stats panels
+func newStatsFrame(name string) *data.Frame {
+	frame := data.NewFrame(name)
+	frame.Fields = append(frame.Fields,
+		data.NewField("Metric", nil, []string{}),
+		data.NewField("Value", nil, []float64{}),
+	)
+	return frame
+}
+
+// ticketStats mirrors calculateTicketStats of the frontend
+type ticketStats struct {
+	Total      int
+	ByStatus   orderedCounts
+	ByPriority orderedCounts
+	// ResolutionHours sums the created to updated time of solved tickets
+	ResolutionHours float64
+	Resolved        int
+}
+
+// add accumulates a page of tickets; tickets without a priority count as normal
+func (s *ticketStats) add(tickets []zendesk.Ticket) {
+	for _, ticket := range tickets {
+		s.Total++
+		s.ByStatus.add(ticket.Status)
+		priority := "normal"
+		if ticket.Priority != nil && *ticket.Priority != "" {
+			priority = *ticket.Priority
+		}
+		s.ByPriority.add(priority)
+		if ticket.Status == "solved" && !ticket.CreatedAt.IsZero() && !ticket.UpdatedAt.IsZero() {
+			s.ResolutionHours += ticket.UpdatedAt.Sub(ticket.CreatedAt).Hours()
+			s.Resolved++
+		}
+	}
+}
+
+// frame converts the stats to the frame of transformStatsToDataFrame
+func (s *ticketStats) frame() *data.Frame {
+	frame := newStatsFrame("stats")
+	frame.AppendRow("Total Tickets", float64(s.Total))
+	s.ByStatus.appendRows(frame, "Status")
+	s.ByPriority.appendRows(frame, "Priority")
+	if s.Resolved > 0 {
+		frame.AppendRow("Average Resolution Time (hours)", s.ResolutionHours/float64(s.Resolved))
+	}
+	return frame
+}
+
+// userStats mirrors calculateUserStats of the frontend
+type userStats struct {
+	Total  int
+	Active int
+	ByRole orderedCounts
+}
+
+// add accumulates a page of users; users without a role count as end-users
+func (s *userStats) add(users []zendesk.User) {
+	for _, user := range users {
+		s.Total++
+		if user.Active {
+			s.Active++
+		}
+		role := user.Role
+		if role == "" {
+			role = "end-user"
+		}
+		s.ByRole.add(role)
+	}
+}
+
+// frame converts the stats to the frame of the frontend userStats query
+func (s *userStats) frame() *data.Frame {
+	frame := newStatsFrame("userStats")
+	frame.AppendRow("Total Users", float64(s.Total))
+	frame.AppendRow("Active Users", float64(s.Active))
+	s.ByRole.appendRows(frame, "Role")
+	return frame
+}
+
+// orgStats mirrors calculateOrganizationStats of the frontend
+type orgStats struct {
+	Total             int
+	WithSharedTickets int
+}
+
+// add accumulates a page of organizations
+func (s *orgStats) add(orgs []zendesk.Organization) {
+	for _, org := range orgs {
+		s.Total++
+		if org.SharedTickets {
+			s.WithSharedTickets++
+		}
+	}
+}
+
+// frame converts the stats to the frame of the frontend orgStats query
+func (s *orgStats) frame() *data.Frame {
+	frame := newStatsFrame("orgStats")
+	frame.AppendRow("Total Organizations", float64(s.Total))
+	frame.AppendRow("With Shared Tickets", float64(s.WithSharedTickets))
+	return frame
+}
+
+// queryStats handles ticket stats queries over every ticket matching the
+// status, priority, assignee and requester options; a time range or user is
+// pushed down to search
+func (ds *Datasource) queryStats(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt, timeFieldSolvedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+	params := ticketListParams(model)
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("stats:%v:%s", params, filter.key())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if stats, ok := cached.(*ticketStats); ok {
+			return &backend.DataResponse{Frames: data.Frames{stats.frame()}}
+		}
+	}
+
+	// Fetch from API
+	stats := &ticketStats{}
+	if needsTicketSearch(params, filter) {
+		var results *zendesk.SearchResults
+		results, err = ds.zendeskClient.Search(ctx, ticketSearchQuery(params, filter), zendesk.SearchTypeTicket, 0)
+		if err == nil {
+			stats.add(filter.tickets(results.Tickets))
+		}
+	} else {
+		err = ds.zendeskClient.WalkTickets(ctx, params, 0, func(tickets []zendesk.Ticket) error {
+			stats.add(tickets)
+			return nil
+		})
+	}
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch tickets: %w", err),
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, stats, cache.DefaultConfig().DefaultTTL)
+
+	return &backend.DataResponse{Frames: data.Frames{stats.frame()}}
+}
+
+// queryUserStats handles user stats queries over every user; a time range
+// is pushed down to search
+func (ds *Datasource) queryUserStats(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("userStats:%s", filter.key())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if stats, ok := cached.(*userStats); ok {
+			return &backend.DataResponse{Frames: data.Frames{stats.frame()}}
+		}
+	}
+
+	// Fetch from API
+	stats := &userStats{}
+	if filter.active() {
+		var results *zendesk.SearchResults
+		results, err = ds.zendeskClient.Search(ctx, filter.searchTerms(), zendesk.SearchTypeUser, 0)
+		if err == nil {
+			stats.add(filter.users(results.Users))
+		}
+	} else {
+		err = ds.zendeskClient.WalkUsers(ctx, nil, 0, func(users []zendesk.User) error {
+			stats.add(users)
+			return nil
+		})
+	}
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch users: %w", err),
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, stats, cache.DefaultConfig().DefaultTTL)
+
+	return &backend.DataResponse{Frames: data.Frames{stats.frame()}}
+}
+
+// queryOrgStats handles organization stats queries over every organization;
+// a time range is pushed down to search
+func (ds *Datasource) queryOrgStats(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("orgStats:%s", filter.key())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if stats, ok := cached.(*orgStats); ok {
+			return &backend.DataResponse{Frames: data.Frames{stats.frame()}}
+		}
+	}
+
+	// Fetch from API
+	stats := &orgStats{}
+	if filter.active() {
+		var results *zendesk.SearchResults
+		results, err = ds.zendeskClient.Search(ctx, filter.searchTerms(), zendesk.SearchTypeOrganization, 0)
+		if err == nil {
+			stats.add(filter.organizations(results.Organizations))
+		}
+	} else {
+		err = ds.zendeskClient.WalkOrganizations(ctx, nil, 0, func(orgs []zendesk.Organization) error {
+			stats.add(orgs)
+			return nil
+		})
+	}
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch organizations: %w", err),
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, stats, cache.DefaultConfig().DefaultTTL)
+
+	return &backend.DataResponse{Frames: data.Frames{stats.frame()}}
+}
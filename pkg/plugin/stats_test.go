@@ -0,0 +1,90 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/experimental"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateGoldenFiles rewrites the golden frames under testdata when set
+const updateGoldenFiles = false
+
+func TestQueryStats_Golden(t *testing.T) {
+	var pages int
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets.json", r.URL.Path)
+		pages++
+		if r.URL.Query().Get("page[after]") == "" {
+			fmt.Fprintf(w, `{"tickets":[
+				{"id":1,"status":"open","priority":"high","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T01:00:00Z"},
+				{"id":2,"status":"solved","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T02:00:00Z"}],
+				"meta":{"has_more":true,"after_cursor":"c1"},
+				"links":{"next":"http://%s/api/v2/tickets.json?page%%5Bafter%%5D=c1"}}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"tickets":[
+			{"id":3,"status":"solved","priority":"high","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T05:00:00Z"},
+			{"id":4,"status":"pending","priority":"low","created_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:30:00Z"}],
+			"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"stats"}`)})
+	require.NoError(t, resp.Error)
+	assert.Equal(t, 2, pages)
+	experimental.CheckGoldenJSONResponse(t, "testdata", "stats", resp, updateGoldenFiles)
+}
+
+func TestQueryUserStats_Golden(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/users.json", r.URL.Path)
+		fmt.Fprint(w, `{"users":[
+			{"id":1,"role":"agent","active":true},
+			{"id":2,"role":"end-user","active":true},
+			{"id":3,"active":false},
+			{"id":4,"role":"admin","active":true}],
+			"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"userStats"}`)})
+	require.NoError(t, resp.Error)
+	experimental.CheckGoldenJSONResponse(t, "testdata", "user_stats", resp, updateGoldenFiles)
+}
+
+func TestQueryOrgStats_Golden(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/organizations.json", r.URL.Path)
+		fmt.Fprint(w, `{"organizations":[
+			{"id":1,"shared_tickets":true},
+			{"id":2},
+			{"id":3,"shared_tickets":true}],
+			"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"orgStats"}`)})
+	require.NoError(t, resp.Error)
+	experimental.CheckGoldenJSONResponse(t, "testdata", "org_stats", resp, updateGoldenFiles)
+}
+
+func TestQueryStats_TimeRange(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/search.json", r.URL.Path)
+		assert.Equal(t, "type:ticket status:solved solved>2024-01-01T00:00:00Z solved<2024-01-02T00:00:00Z", r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"results":[{"id":1,"result_type":"ticket","status":"solved"}],"count":1}`)
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"stats","status":"solved","timeField":"solved_at"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(24 * time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	value, _ := resp.Frames[0].FieldByName("Value")
+	assert.Equal(t, 1.0, value.At(0))
+}
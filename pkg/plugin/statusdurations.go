@@ -0,0 +1,238 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// trackedStatuses are the ticket statuses whose dwell time is measured;
+// closed and deleted tickets no longer change
+var trackedStatuses = []string{"new", "open", "pending", "hold", "solved"}
+
+// statusDurationPercentiles are the percentiles reported per status
+var statusDurationPercentiles = []float64{50, 75, 90, 95}
+
+// statusDurationsNotice explains which tickets without a status change in the
+// range are counted
+const statusDurationsNotice = "Tickets without a status change in the range are counted when unsolved and not updated since the range ended; " +
+	"others without a change, such as tickets solved throughout the range, are not included."
+
+// statusDurationsCapNotice tells that the events or unchanged tickets stopped at maxQueryRows
+var statusDurationsCapNotice = fmt.Sprintf("Only the first %d ticket events or unchanged tickets are counted; narrow the time range to include the rest.", maxQueryRows)
+
+// statusDurationsResult holds the status changes in a time range and the
+// unsolved tickets not updated since the range ended, whose status was
+// therefore unchanged for the range unless a change says otherwise
+type statusDurationsResult struct {
+	Changes   []zendesk.TicketChange
+	Unchanged []zendesk.Ticket
+	// Capped is set when the events or the unchanged tickets stopped at maxQueryRows
+	Capped bool
+}
+
+// statusDurations returns, per ticket, the minutes spent in each tracked
+// status between from and to, rebuilt from status changes. Unchanged tickets
+// without a status change spent the range, from their creation on, in their
+// current status.
+func statusDurations(changes []zendesk.TicketChange, unchanged []zendesk.Ticket, from, to time.Time) map[int64]map[string]float64 {
+	byTicket := map[int64][]zendesk.TicketChange{}
+	for _, change := range changes {
+		if change.FieldName == "status" {
+			byTicket[change.TicketID] = append(byTicket[change.TicketID], change)
+		}
+	}
+
+	durations := make(map[int64]map[string]float64, len(byTicket))
+	for ticketID, ticketChanges := range byTicket {
+		sort.SliceStable(ticketChanges, func(i, j int) bool { return ticketChanges[i].At.Before(ticketChanges[j].At) })
+
+		dwell := map[string]float64{}
+		add := func(status string, start, end time.Time) {
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				dwell[status] += end.Sub(start).Minutes()
+			}
+		}
+
+		// A ticket that changed status inside the range was in the previous
+		// status since the range started
+		status, since := "", from
+		if first := ticketChanges[0]; first.Type != zendesk.ChangeEventCreate && first.PreviousValue != "" {
+			status = first.PreviousValue
+		}
+		for _, change := range ticketChanges {
+			if status != "" {
+				add(status, since, change.At)
+			}
+			status, since = change.Value, change.At
+			if status == "closed" || status == "deleted" {
+				status = ""
+				break
+			}
+		}
+		if status != "" {
+			add(status, since, to)
+		}
+
+		durations[ticketID] = dwell
+	}
+
+	for _, ticket := range unchanged {
+		if _, ok := durations[ticket.ID]; ok {
+			continue
+		}
+		start := from
+		if ticket.CreatedAt.After(start) {
+			start = ticket.CreatedAt
+		}
+		if to.After(start) {
+			durations[ticket.ID] = map[string]float64{ticket.Status: to.Sub(start).Minutes()}
+		}
+	}
+	return durations
+}
+
+// queryStatusDurations handles time-in-status queries over the query time
+// range. Unsolved tickets without a status change are found by search.
+func (ds *Datasource) queryStatusDurations(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	opts := zendesk.IncrementalOptions{
+		StartTime: query.TimeRange.From,
+		EndTime:   query.TimeRange.To,
+		MaxRows:   maxQueryRows,
+	}
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("statusDurations:%d:%d", opts.StartTime.Unix(), opts.EndTime.Unix())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if result, ok := cached.(*statusDurationsResult); ok {
+			return ds.statusDurationsToDataFrames(result, query)
+		}
+	}
+
+	// Fetch from API
+	result := &statusDurationsResult{}
+	events := 0
+	_, err := ds.zendeskClient.IncrementalTicketEvents(ctx, opts, func(page []zendesk.TicketEvent) error {
+		events += len(page)
+		for _, event := range page {
+			result.Changes = append(result.Changes, event.Changes()...)
+		}
+		return nil
+	})
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch ticket events: %w", err),
+		}
+	}
+
+	searchQuery := fmt.Sprintf("status<solved updated<%s", statusDurationsEnd(query).UTC().Format(time.RFC3339))
+	results, err := ds.zendeskClient.Search(ctx, searchQuery, zendesk.SearchTypeTicket, maxQueryRows)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to search unchanged tickets: %w", err),
+		}
+	}
+	result.Unchanged = results.Tickets
+	result.Capped = events >= maxQueryRows || results.Count > len(results.Tickets)
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, result, cache.DefaultConfig().DefaultTTL)
+
+	return ds.statusDurationsToDataFrames(result, query)
+}
+
+// statusDurationsEnd returns the end of the query time range, or now for
+// ranges without an end or ending in the future
+func statusDurationsEnd(query backend.DataQuery) time.Time {
+	to := query.TimeRange.To
+	if now := time.Now(); to.IsZero() || to.After(now) {
+		to = now
+	}
+	return to
+}
+
+// statusDurationsToDataFrames converts status changes to a per-ticket dwell
+// time frame and a per-status percentile frame, in minutes
+func (ds *Datasource) statusDurationsToDataFrames(result *statusDurationsResult, query backend.DataQuery) *backend.DataResponse {
+	durations := statusDurations(result.Changes, result.Unchanged, query.TimeRange.From, statusDurationsEnd(query))
+
+	ticketIDs := make([]int64, 0, len(durations))
+	for ticketID := range durations {
+		ticketIDs = append(ticketIDs, ticketID)
+	}
+	sort.Slice(ticketIDs, func(i, j int) bool { return ticketIDs[i] < ticketIDs[j] })
+
+	perTicket := data.NewFrame("status_durations")
+	perTicket.Fields = append(perTicket.Fields, data.NewField("ticket_id", nil, []int64{}))
+	for _, status := range trackedStatuses {
+		field := data.NewField(status+"_minutes", nil, []float64{})
+		field.Config = &data.FieldConfig{Unit: "m"}
+		perTicket.Fields = append(perTicket.Fields, field)
+	}
+
+	samples := map[string][]float64{}
+	for _, ticketID := range ticketIDs {
+		row := []interface{}{ticketID}
+		for _, status := range trackedStatuses {
+			minutes := durations[ticketID][status]
+			row = append(row, minutes)
+			if minutes > 0 {
+				samples[status] = append(samples[status], minutes)
+			}
+		}
+		perTicket.AppendRow(row...)
+	}
+	perTicket.AppendNotices(data.Notice{Severity: data.NoticeSeverityInfo, Text: statusDurationsNotice})
+	if result.Capped {
+		perTicket.AppendNotices(data.Notice{Severity: data.NoticeSeverityWarning, Text: statusDurationsCapNotice})
+	}
+
+	aggregate := data.NewFrame("status_duration_percentiles")
+	aggregate.Fields = append(aggregate.Fields,
+		data.NewField("status", nil, []string{}),
+		data.NewField("tickets", nil, []int64{}),
+		data.NewField("mean_minutes", nil, []*float64{}),
+	)
+	for _, p := range statusDurationPercentiles {
+		aggregate.Fields = append(aggregate.Fields, data.NewField(fmt.Sprintf("p%.0f_minutes", p), nil, []*float64{}))
+	}
+	aggregate.Fields = append(aggregate.Fields, data.NewField("max_minutes", nil, []*float64{}))
+
+	for _, status := range trackedStatuses {
+		values := samples[status]
+		row := []interface{}{status, int64(len(values))}
+		if len(values) == 0 {
+			for i := 0; i < len(statusDurationPercentiles)+2; i++ {
+				row = append(row, (*float64)(nil))
+			}
+			aggregate.AppendRow(row...)
+			continue
+		}
+		avg := mean(values)
+		row = append(row, &avg)
+		for _, p := range statusDurationPercentiles {
+			v := percentile(values, p)
+			row = append(row, &v)
+		}
+		max := percentile(values, 100)
+		row = append(row, &max)
+		aggregate.AppendRow(row...)
+	}
+
+	return &backend.DataResponse{
+		Frames: data.Frames{perTicket, aggregate},
+	}
+}
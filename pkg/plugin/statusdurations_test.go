@@ -0,0 +1,144 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+func TestStatusDurations(t *testing.T) {
+	from := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	to := from.Add(4 * time.Hour)
+	at := func(minutes int) time.Time { return from.Add(time.Duration(minutes) * time.Minute) }
+	change := func(ticketID int64, minutes int, changeType, value, previous string) zendesk.TicketChange {
+		return zendesk.TicketChange{TicketID: ticketID, At: at(minutes), Type: changeType, FieldName: "status", Value: value, PreviousValue: previous}
+	}
+
+	changes := []zendesk.TicketChange{
+		// Ticket 1 is created inside the range and closed before it ends
+		change(1, 30, zendesk.ChangeEventCreate, "new", ""),
+		change(1, 60, zendesk.ChangeEventChange, "open", "new"),
+		change(1, 120, zendesk.ChangeEventChange, "solved", "open"),
+		change(1, 180, zendesk.ChangeEventChange, "closed", "solved"),
+		// Ticket 2 was already pending when the range started
+		change(2, 90, zendesk.ChangeEventChange, "open", "pending"),
+		// Other fields are ignored
+		{TicketID: 3, At: at(10), Type: zendesk.ChangeEventChange, FieldName: "priority", Value: "high"},
+	}
+
+	unchanged := []zendesk.Ticket{
+		// Ticket 2 changed in the range, so its changes are used
+		{ID: 2, Status: "open", CreatedAt: from.Add(-time.Hour)},
+		// Ticket 4 was on hold for the whole range, ticket 5 since its creation
+		{ID: 4, Status: "hold", CreatedAt: from.Add(-time.Hour)},
+		{ID: 5, Status: "pending", CreatedAt: at(180)},
+	}
+
+	durations := statusDurations(changes, unchanged, from, to)
+	require.Len(t, durations, 4)
+	assert.Equal(t, map[string]float64{"new": 30, "open": 60, "solved": 60}, durations[1])
+	assert.Equal(t, map[string]float64{"pending": 90, "open": 150}, durations[2])
+	assert.Equal(t, map[string]float64{"hold": 240}, durations[4])
+	assert.Equal(t, map[string]float64{"pending": 60}, durations[5])
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{40, 10, 30, 20}
+	assert.Equal(t, 10.0, percentile(values, 0))
+	assert.Equal(t, 25.0, percentile(values, 50))
+	assert.Equal(t, 40.0, percentile(values, 100))
+	assert.True(t, math.IsNaN(percentile(nil, 50)))
+}
+
+func TestStatusDurationsToDataFrames(t *testing.T) {
+	ds := newTestDatasource(t, http.NotFoundHandler())
+	from := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	changes := []zendesk.TicketChange{
+		{TicketID: 1, At: from.Add(time.Hour), Type: zendesk.ChangeEventChange, FieldName: "status", Value: "solved", PreviousValue: "open"},
+		{TicketID: 2, At: from.Add(3 * time.Hour), Type: zendesk.ChangeEventChange, FieldName: "status", Value: "solved", PreviousValue: "open"},
+	}
+
+	resp := ds.statusDurationsToDataFrames(&statusDurationsResult{Changes: changes}, backend.DataQuery{
+		TimeRange: backend.TimeRange{From: from, To: from.Add(4 * time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 2)
+
+	perTicket := resp.Frames[0]
+	assert.Equal(t, 2, perTicket.Rows())
+	require.NotNil(t, perTicket.Meta)
+	assert.Len(t, perTicket.Meta.Notices, 1)
+	assert.Equal(t, "open_minutes", perTicket.Fields[2].Name)
+
+	aggregate := resp.Frames[1]
+	assert.Equal(t, len(trackedStatuses), aggregate.Rows())
+	assert.Nil(t, aggregate.Meta)
+	// The open row is second: tickets were open for 60 and 180 minutes
+	assert.Equal(t, "open", aggregate.Fields[0].At(1))
+	assert.Equal(t, int64(2), aggregate.Fields[1].At(1))
+	assert.Equal(t, 120.0, *aggregate.Fields[2].At(1).(*float64))
+	assert.Equal(t, 120.0, *aggregate.Fields[3].At(1).(*float64))
+	assert.Nil(t, aggregate.Fields[2].At(0))
+}
+
+func TestQueryStatusDurations_UnchangedTickets(t *testing.T) {
+	from := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	to := from.Add(4 * time.Hour)
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/incremental/ticket_events.json":
+			fmt.Fprint(w, `{"ticket_events":[],"end_time":1704096001}`)
+		case "/api/v2/search.json":
+			assert.Equal(t, "type:ticket status<solved updated<2024-01-01T12:00:00Z", r.URL.Query().Get("query"))
+			fmt.Fprint(w, `{"results":[{"id":4,"result_type":"ticket","status":"hold","created_at":"2023-12-01T00:00:00Z"}],"count":1}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.queryStatusDurations(context.Background(), backend.DataQuery{
+		TimeRange: backend.TimeRange{From: from, To: to},
+	}, nil)
+	require.NoError(t, resp.Error)
+	perTicket := resp.Frames[0]
+	require.Equal(t, 1, perTicket.Rows())
+	assert.Equal(t, int64(4), perTicket.Fields[0].At(0))
+	hold, _ := perTicket.FieldByName("hold_minutes")
+	assert.Equal(t, 240.0, hold.At(0))
+	require.Len(t, perTicket.Meta.Notices, 1)
+}
+
+func TestQueryStatusDurations_SearchCap(t *testing.T) {
+	from := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/incremental/ticket_events.json":
+			fmt.Fprint(w, `{"ticket_events":[],"end_time":1704096001}`)
+		case "/api/v2/search.json":
+			fmt.Fprint(w, `{"results":[],"count":20000}`)
+		case "/api/v2/search/export.json":
+			// The export ends before every matching ticket was read
+			fmt.Fprint(w, `{"results":[{"id":4,"result_type":"ticket","status":"hold","created_at":"2023-12-01T00:00:00Z"}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.queryStatusDurations(context.Background(), backend.DataQuery{
+		TimeRange: backend.TimeRange{From: from, To: from.Add(4 * time.Hour)},
+	}, nil)
+	require.NoError(t, resp.Error)
+	perTicket := resp.Frames[0]
+	require.Len(t, perTicket.Meta.Notices, 2)
+	assert.Equal(t, statusDurationsCapNotice, perTicket.Meta.Notices[1].Text)
+	assert.Nil(t, resp.Frames[1].Meta)
+}
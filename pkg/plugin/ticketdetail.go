@@ -0,0 +1,235 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// ticketDetail holds everything a ticket detail dashboard shows for one ticket
+type ticketDetail struct {
+	Ticket    *zendesk.Ticket
+	Sideloads *zendesk.Sideloads
+	Comments  []zendesk.Comment
+	Authors   *zendesk.Sideloads
+	Audits    []zendesk.TicketAudit
+	Metric    *zendesk.TicketMetric
+}
+
+// queryTicketByID handles ticket detail queries, returning the ticket with
+// every custom field unless customFields is set, its comments, its audit
+// events and its metrics as separate frames
+func (ds *Datasource) queryTicketByID(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	ticketID := model.TicketID
+	if ticketID <= 0 {
+		return &backend.DataResponse{
+			Error:  fmt.Errorf("ticketId is required"),
+			Status: backend.StatusBadRequest,
+		}
+	}
+	if model.CustomFields == nil {
+		model.CustomFields = []interface{}{allCustomFields}
+	}
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("ticketById:%d", ticketID)
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if detail, ok := cached.(*ticketDetail); ok {
+			return ds.ticketDetailToDataFrames(ctx, detail, model)
+		}
+	}
+
+	// Fetch from API
+	detail, err := ds.fetchTicketDetail(ctx, ticketID)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: err,
+		}
+	}
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, detail, cache.DefaultConfig().DefaultTTL)
+
+	return ds.ticketDetailToDataFrames(ctx, detail, model)
+}
+
+// fetchTicketDetail fetches a ticket with its comments, audits and metrics
+func (ds *Datasource) fetchTicketDetail(ctx context.Context, ticketID int64) (*ticketDetail, error) {
+	detail := &ticketDetail{}
+	var err error
+	detail.Ticket, detail.Sideloads, err = ds.zendeskClient.GetTicket(ctx, ticketID, ticketIncludes...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ticket: %w", err)
+	}
+	detail.Comments, detail.Authors, err = ds.zendeskClient.ListTicketComments(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ticket comments: %w", err)
+	}
+	detail.Audits, err = ds.zendeskClient.ListTicketAudits(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ticket audits: %w", err)
+	}
+	// Archived tickets have no metrics
+	detail.Metric, err = ds.zendeskClient.GetTicketMetric(ctx, ticketID)
+	if err != nil && !errors.Is(err, zendesk.ErrNotFound) {
+		return nil, fmt.Errorf("failed to fetch ticket metrics: %w", err)
+	}
+	return detail, nil
+}
+
+// joinIDs joins ids with commas for a frame column
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+// viaChannel returns the channel of an optional via
+func viaChannel(via *zendesk.Via) string {
+	if via == nil {
+		return ""
+	}
+	return via.Channel
+}
+
+// ticketDetailToDataFrames converts a ticket detail to the "tickets",
+// "comments", "audit_events" and "ticket_metrics" frames
+func (ds *Datasource) ticketDetailToDataFrames(ctx context.Context, detail *ticketDetail, model *QueryModel) *backend.DataResponse {
+	tickets := []zendesk.Ticket{*detail.Ticket}
+	lookups, err := ds.ticketLookups(ctx, tickets, detail.Sideloads, model)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: err,
+		}
+	}
+	ticketFrame := ds.ticketsToDataFrame(tickets, lookups).Frames[0]
+
+	// Attributes only shown for a single ticket
+	ticket := detail.Ticket
+	description := ""
+	if ticket.Description != nil {
+		description = *ticket.Description
+	}
+	ticketFrame.Fields = append(ticketFrame.Fields,
+		data.NewField("description", nil, []string{description}),
+		data.NewField("external_id", nil, []*string{ticket.ExternalID}),
+		data.NewField("submitter_id", nil, []int64{ticket.SubmitterID}),
+		data.NewField("collaborator_ids", nil, []string{joinIDs(ticket.CollaboratorIDs)}),
+		data.NewField("follower_ids", nil, []string{joinIDs(ticket.FollowerIDs)}),
+		data.NewField("problem_id", nil, []*int64{ticket.ProblemID}),
+		data.NewField("has_incidents", nil, []bool{ticket.HasIncidents}),
+		data.NewField("is_public", nil, []bool{ticket.IsPublic}),
+	)
+
+	// Comment and audit authors share one name lookup
+	var authorIDs []int64
+	for _, comment := range detail.Comments {
+		authorIDs = append(authorIDs, comment.AuthorID)
+	}
+	for _, audit := range detail.Audits {
+		authorIDs = append(authorIDs, audit.AuthorID)
+	}
+	known := map[int64]zendesk.User{}
+	for _, sideloads := range []*zendesk.Sideloads{detail.Sideloads, detail.Authors} {
+		if sideloads == nil {
+			continue
+		}
+		for id, user := range sideloads.Users {
+			known[id] = user
+		}
+	}
+	authors, err := ds.userNames(ctx, authorIDs, known)
+	if err != nil {
+		if fatal := lookupError(ctx, err); fatal != nil {
+			return &backend.DataResponse{
+				Error: fatal,
+			}
+		}
+	}
+
+	comments := data.NewFrame("comments")
+	comments.Fields = append(comments.Fields,
+		data.NewField("id", nil, []int64{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("author_id", nil, []int64{}),
+		data.NewField("author_name", nil, []string{}),
+		data.NewField("public", nil, []bool{}),
+		data.NewField("channel", nil, []string{}),
+		data.NewField("body", nil, []string{}),
+		data.NewField("attachments", nil, []int64{}),
+	)
+	for _, comment := range detail.Comments {
+		body := comment.PlainBody
+		if body == "" {
+			body = comment.Body
+		}
+		comments.AppendRow(
+			comment.ID,
+			comment.CreatedAt,
+			comment.AuthorID,
+			authors[comment.AuthorID],
+			comment.Public,
+			viaChannel(comment.Via),
+			body,
+			int64(len(comment.Attachments)),
+		)
+	}
+
+	events := data.NewFrame("audit_events")
+	events.Fields = append(events.Fields,
+		data.NewField("audit_id", nil, []int64{}),
+		data.NewField("created_at", nil, []time.Time{}),
+		data.NewField("author_id", nil, []int64{}),
+		data.NewField("author_name", nil, []string{}),
+		data.NewField("channel", nil, []string{}),
+		data.NewField("event_id", nil, []int64{}),
+		data.NewField("type", nil, []string{}),
+		data.NewField("field_name", nil, []string{}),
+		data.NewField("value", nil, []string{}),
+		data.NewField("previous_value", nil, []string{}),
+	)
+	for _, audit := range detail.Audits {
+		for _, event := range audit.Events {
+			value := string(event.Value)
+			if event.Type == zendesk.ChangeEventComment {
+				value = event.Body
+			}
+			events.AppendRow(
+				audit.ID,
+				audit.CreatedAt,
+				audit.AuthorID,
+				authors[audit.AuthorID],
+				viaChannel(audit.Via),
+				event.ID,
+				event.Type,
+				event.FieldName,
+				value,
+				string(event.PreviousValue),
+			)
+		}
+	}
+
+	if err != nil {
+		comments.AppendNotices(data.Notice{Severity: data.NoticeSeverityWarning, Text: lookupNotice})
+	}
+
+	frames := data.Frames{ticketFrame, comments, events}
+	if detail.Metric != nil {
+		metrics := ds.ticketMetricsToDataFrame(&ticketMetricsResult{Metrics: []zendesk.TicketMetric{*detail.Metric}})
+		frames = append(frames, metrics.Frames...)
+	}
+	return &backend.DataResponse{
+		Frames: frames,
+	}
+}
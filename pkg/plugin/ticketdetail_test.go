@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTicketByID(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ticket_fields.json":
+			fmt.Fprint(w, `{"ticket_fields":[{"id":10,"type":"text","title":"Region","removable":true,"active":true}],"meta":{"has_more":false}}`)
+		case "/api/v2/tickets/42.json":
+			fmt.Fprint(w, `{"ticket":{"id":42,"subject":"Printer","status":"open","requester_id":7,"description":"It is on fire",
+				"collaborator_ids":[8,9],"custom_fields":[{"id":10,"value":"EMEA"}],"created_at":"2024-01-01T00:00:00Z"},
+				"users":[{"id":7,"name":"Alice"}]}`)
+		case "/api/v2/tickets/42/comments.json":
+			fmt.Fprint(w, `{"comments":[
+				{"id":100,"author_id":7,"body":"<p>Help</p>","plain_body":"Help","public":true,"via":{"channel":"email"},"created_at":"2024-01-01T00:00:00Z"},
+				{"id":101,"author_id":8,"body":"Looking","public":false,"via":{"channel":"web"},"created_at":"2024-01-01T01:00:00Z",
+				 "attachments":[{"id":1,"file_name":"log.txt"}]}],
+				"users":[{"id":8,"name":"Bob"}],"meta":{"has_more":false}}`)
+		case "/api/v2/tickets/42/audits.json":
+			fmt.Fprint(w, `{"audits":[{"id":500,"ticket_id":42,"author_id":8,"created_at":"2024-01-01T01:00:00Z","via":{"channel":"web"},
+				"events":[{"id":1,"type":"Change","field_name":"status","value":"open","previous_value":"new"},
+				{"id":2,"type":"Comment","body":"Looking","public":false}]}],"meta":{"has_more":false}}`)
+		case "/api/v2/tickets/42/metrics.json":
+			fmt.Fprint(w, `{"ticket_metric":{"id":9,"ticket_id":42,"replies":1}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"ticketById","ticketId":42}`)})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 4)
+	assert.Equal(t, "tickets", resp.Frames[0].Name)
+	assert.Equal(t, "comments", resp.Frames[1].Name)
+	assert.Equal(t, "audit_events", resp.Frames[2].Name)
+	assert.Equal(t, "ticket_metrics", resp.Frames[3].Name)
+
+	ticket := resp.Frames[0]
+	region, _ := ticket.FieldByName("Region")
+	require.NotNil(t, region)
+	v, ok := region.ConcreteAt(0)
+	require.True(t, ok)
+	assert.Equal(t, "EMEA", v)
+	collaborators, _ := ticket.FieldByName("collaborator_ids")
+	assert.Equal(t, "8,9", collaborators.At(0))
+	description, _ := ticket.FieldByName("description")
+	assert.Equal(t, "It is on fire", description.At(0))
+
+	comments := resp.Frames[1]
+	require.Equal(t, 2, comments.Rows())
+	author, _ := comments.FieldByName("author_name")
+	assert.Equal(t, []interface{}{"Alice", "Bob"}, []interface{}{author.At(0), author.At(1)})
+	public, _ := comments.FieldByName("public")
+	assert.Equal(t, false, public.At(1))
+	body, _ := comments.FieldByName("body")
+	assert.Equal(t, "Help", body.At(0))
+	attachments, _ := comments.FieldByName("attachments")
+	assert.Equal(t, int64(1), attachments.At(1))
+
+	events := resp.Frames[2]
+	require.Equal(t, 2, events.Rows())
+	previous, _ := events.FieldByName("previous_value")
+	assert.Equal(t, "new", previous.At(0))
+	value, _ := events.FieldByName("value")
+	assert.Equal(t, "Looking", value.At(1))
+}
+
+func TestQueryTicketByID_RequiresTicketID(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL.Path)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"ticketById"}`)})
+	require.Error(t, resp.Error)
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
@@ -0,0 +1,181 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// ticketFieldsCacheKey caches the ticket field definitions of the account
+const ticketFieldsCacheKey = "ticketFields"
+
+// allCustomFields selects every active custom field
+const allCustomFields = "*"
+
+// listTicketFields returns the ticket field definitions, from cache when possible
+func (ds *Datasource) listTicketFields(ctx context.Context) ([]zendesk.TicketField, error) {
+	if cached, found := ds.cacheManager.Get(ticketFieldsCacheKey); found {
+		if fields, ok := cached.([]zendesk.TicketField); ok {
+			return fields, nil
+		}
+	}
+
+	fields, err := ds.zendeskClient.ListTicketFields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ds.cacheManager.Set(ticketFieldsCacheKey, fields, cache.DefaultConfig().DefaultTTL)
+	return fields, nil
+}
+
+// customField is a custom ticket field with the name of its frame column
+type customField struct {
+	zendesk.TicketField
+	Column string
+}
+
+// customFieldColumnNames returns the frame column names of the custom
+// fields. A field is named by its title, followed by its id when another
+// custom field or a ticket column has the same name.
+func customFieldColumnNames(custom []zendesk.TicketField) map[int64]string {
+	titles := map[string]int{}
+	for _, column := range recordColumns["tickets"] {
+		titles[column]++
+	}
+	for _, field := range custom {
+		titles[field.Title]++
+	}
+
+	names := make(map[int64]string, len(custom))
+	for _, field := range custom {
+		names[field.ID] = field.Title
+		if titles[field.Title] > 1 {
+			names[field.ID] = fmt.Sprintf("%s (%d)", field.Title, field.ID)
+		}
+	}
+	return names
+}
+
+// selectCustomFields returns the custom fields chosen by selection, in the
+// order chosen. Fields are chosen by id, title or column name; "*" chooses
+// every active custom field in position order. A title shared by several
+// custom fields is rejected as ambiguous.
+func selectCustomFields(fields []zendesk.TicketField, selection []interface{}) ([]customField, error) {
+	var custom []zendesk.TicketField
+	for _, field := range fields {
+		if field.IsCustom() {
+			custom = append(custom, field)
+		}
+	}
+	names := customFieldColumnNames(custom)
+
+	var selected []customField
+	for _, choice := range selection {
+		if choice == allCustomFields {
+			var active []customField
+			for _, field := range custom {
+				if field.Active {
+					active = append(active, customField{TicketField: field, Column: names[field.ID]})
+				}
+			}
+			sort.SliceStable(active, func(i, j int) bool { return active[i].Position < active[j].Position })
+			return active, nil
+		}
+
+		var matches []customField
+		for _, field := range custom {
+			found := false
+			switch v := choice.(type) {
+			case float64:
+				found = field.ID == int64(v)
+			case string:
+				found = field.Title == v || names[field.ID] == v
+			}
+			if found {
+				matches = append(matches, customField{TicketField: field, Column: names[field.ID]})
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("unknown custom field: %v", choice)
+		case 1:
+			selected = append(selected, matches[0])
+		default:
+			return nil, fmt.Errorf("ambiguous custom field: %v matches %d fields, choose it by id", choice, len(matches))
+		}
+	}
+	return selected, nil
+}
+
+// ticketCustomFields returns the custom fields chosen by the customFields query option
+func (ds *Datasource) ticketCustomFields(ctx context.Context, model *QueryModel) ([]customField, error) {
+	if len(model.CustomFields) == 0 {
+		return nil, nil
+	}
+	fields, err := ds.listTicketFields(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ticket fields: %w", err)
+	}
+	return selectCustomFields(fields, model.CustomFields)
+}
+
+// customFieldColumn returns an empty frame field typed after the custom field
+func customFieldColumn(field customField) *data.Field {
+	switch field.Type {
+	case zendesk.FieldTypeInteger:
+		return data.NewField(field.Column, nil, []*int64{})
+	case zendesk.FieldTypeDecimal:
+		return data.NewField(field.Column, nil, []*float64{})
+	case zendesk.FieldTypeDate:
+		return data.NewField(field.Column, nil, []*time.Time{})
+	case zendesk.FieldTypeCheckbox:
+		return data.NewField(field.Column, nil, []*bool{})
+	default:
+		return data.NewField(field.Column, nil, []*string{})
+	}
+}
+
+// customFieldColumns returns one column per custom field holding the
+// decoded values of tickets; values that fail to decode are left empty
+func customFieldColumns(tickets []zendesk.Ticket, fields []customField) []*data.Field {
+	columns := make([]*data.Field, len(fields))
+	for i, field := range fields {
+		column := customFieldColumn(field)
+		for _, ticket := range tickets {
+			value, err := field.Decode(ticket.CustomFieldValue(field.ID))
+			if err != nil {
+				column.Extend(1)
+				continue
+			}
+			column.Append(value)
+		}
+		columns[i] = column
+	}
+	return columns
+}
+
+// handleTicketFields lists the active custom ticket fields as options for
+// the customFields query option
+func (ds *Datasource) handleTicketFields(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	fields, err := ds.listTicketFields(ctx)
+	if err != nil {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: 500,
+			Body:   []byte(fmt.Sprintf(`{"error":"Failed to fetch ticket fields: %v"}`, err)),
+		})
+	}
+
+	selected, _ := selectCustomFields(fields, []interface{}{allCustomFields})
+	options := make([]variableOption, 0, len(selected))
+	for _, field := range selected {
+		options = append(options, variableOption{Text: field.Column, Value: field.ID})
+	}
+	return sendJSON(sender, options)
+}
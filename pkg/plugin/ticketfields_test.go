@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+func TestSelectCustomFields(t *testing.T) {
+	fields := []zendesk.TicketField{
+		{ID: 1, Title: "Subject"},
+		{ID: 2, Title: "Category", Removable: true, Active: true, Position: 2},
+		{ID: 3, Title: "Region", Removable: true, Active: true, Position: 1},
+		{ID: 4, Title: "Legacy", Removable: true},
+	}
+
+	selected, err := selectCustomFields(fields, []interface{}{"Category", float64(4)})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, int64(2), selected[0].ID)
+	assert.Equal(t, int64(4), selected[1].ID)
+
+	all, err := selectCustomFields(fields, []interface{}{"*"})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "Region", all[0].Title)
+
+	// System fields cannot be chosen
+	_, err = selectCustomFields(fields, []interface{}{"Subject"})
+	assert.Error(t, err)
+}
+
+func TestSelectCustomFields_ColumnNames(t *testing.T) {
+	fields := []zendesk.TicketField{
+		{ID: 1, Title: "Region", Removable: true, Active: true, Position: 1},
+		{ID: 2, Title: "Region", Removable: true, Active: true, Position: 2},
+		{ID: 3, Title: "status", Removable: true, Active: true, Position: 3},
+		{ID: 4, Title: "Category", Removable: true, Active: true, Position: 4},
+	}
+
+	all, err := selectCustomFields(fields, []interface{}{"*"})
+	require.NoError(t, err)
+	var columns []string
+	for _, field := range all {
+		columns = append(columns, field.Column)
+	}
+	assert.Equal(t, []string{"Region (1)", "Region (2)", "status (3)", "Category"}, columns)
+
+	selected, err := selectCustomFields(fields, []interface{}{"Region (2)", "status", float64(1)})
+	require.NoError(t, err)
+	require.Len(t, selected, 3)
+	assert.Equal(t, int64(2), selected[0].ID)
+	assert.Equal(t, int64(3), selected[1].ID)
+	assert.Equal(t, int64(1), selected[2].ID)
+
+	_, err = selectCustomFields(fields, []interface{}{"Region"})
+	assert.EqualError(t, err, "ambiguous custom field: Region matches 2 fields, choose it by id")
+}
+
+func TestQueryTickets_CustomFields(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ticket_fields.json":
+			fmt.Fprint(w, `{"ticket_fields":[
+				{"id":10,"type":"integer","title":"Units","removable":true,"active":true},
+				{"id":11,"type":"date","title":"Renewal","removable":true,"active":true},
+				{"id":12,"type":"tagger","title":"Category","removable":true,"active":true,"custom_field_options":[{"name":"Hardware","value":"hw"}]}
+			],"meta":{"has_more":false}}`)
+		case "/api/v2/tickets.json":
+			fmt.Fprint(w, `{"tickets":[
+				{"id":1,"status":"open","custom_fields":[{"id":10,"value":"3"},{"id":11,"value":"2024-03-01"},{"id":12,"value":"hw"}]},
+				{"id":2,"status":"open","custom_fields":[{"id":10,"value":"not a number"}]}
+			],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","customFields":["Units",11,"Category"]}`)})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+
+	units, _ := frame.FieldByName("Units")
+	require.NotNil(t, units)
+	assert.Equal(t, int64(3), *units.At(0).(*int64))
+	assert.Nil(t, units.At(1))
+
+	renewal, _ := frame.FieldByName("Renewal")
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), *renewal.At(0).(*time.Time))
+
+	category, _ := frame.FieldByName("Category")
+	assert.Equal(t, "Hardware", *category.At(0).(*string))
+	assert.Nil(t, category.At(1))
+}
+
+func TestQueryTickets_UnknownCustomField(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/ticket_fields.json":
+			fmt.Fprint(w, `{"ticket_fields":[],"meta":{"has_more":false}}`)
+		default:
+			fmt.Fprint(w, `{"tickets":[{"id":1,"status":"open"}],"meta":{"has_more":false}}`)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"tickets","customFields":["Missing"]}`)})
+	assert.Error(t, resp.Error)
+}
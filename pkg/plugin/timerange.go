@@ -0,0 +1,191 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// Time fields a query can be bound to by the dashboard time range
+const (
+	timeFieldCreatedAt = "created_at"
+	timeFieldUpdatedAt = "updated_at"
+	timeFieldSolvedAt  = "solved_at"
+)
+
+// searchTimeKeywords maps time fields to their Zendesk search keyword
+var searchTimeKeywords = map[string]string{
+	timeFieldCreatedAt: "created",
+	timeFieldUpdatedAt: "updated",
+	timeFieldSolvedAt:  "solved",
+}
+
+// timeFilter binds the rows of a query to the dashboard time range on one
+// time field. A query without a time range matches every row.
+type timeFilter struct {
+	Field string
+	From  time.Time
+	To    time.Time
+}
+
+// newTimeFilter reads the "timeField" of a query, the first supported field
+// by default, and rejects fields outside supported
+func newTimeFilter(query backend.DataQuery, model *QueryModel, supported ...string) (*timeFilter, error) {
+	field := supported[0]
+	if model.TimeField != "" {
+		field = model.TimeField
+	}
+	for _, s := range supported {
+		if s == field {
+			return &timeFilter{Field: field, From: query.TimeRange.From, To: query.TimeRange.To}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported time field: %s", field)
+}
+
+// active reports whether the query has a time range to filter by
+func (f *timeFilter) active() bool {
+	return !f.From.IsZero() || !f.To.IsZero()
+}
+
+// key identifies the filter in cache keys
+func (f *timeFilter) key() string {
+	if !f.active() {
+		return f.Field
+	}
+	return fmt.Sprintf("%s:%d:%d", f.Field, f.From.Unix(), f.To.Unix())
+}
+
+// contains reports whether t falls within the time range, bounds included
+func (f *timeFilter) contains(t time.Time) bool {
+	if !f.From.IsZero() && t.Before(f.From) {
+		return false
+	}
+	return f.To.IsZero() || !t.After(f.To)
+}
+
+// searchTerms returns the Zendesk search terms restricting results to the
+// time range, e.g. "created>2024-01-01T00:00:00Z created<2024-01-02T00:00:00Z"
+func (f *timeFilter) searchTerms() string {
+	if !f.active() {
+		return ""
+	}
+	keyword := searchTimeKeywords[f.Field]
+	var terms []string
+	if !f.From.IsZero() {
+		terms = append(terms, fmt.Sprintf("%s>%s", keyword, f.From.UTC().Format(time.RFC3339)))
+	}
+	if !f.To.IsZero() {
+		terms = append(terms, fmt.Sprintf("%s<%s", keyword, f.To.UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(terms, " ")
+}
+
+// withSearchTerms appends the time range to a Zendesk search query
+func (f *timeFilter) withSearchTerms(query string) string {
+	terms := f.searchTerms()
+	if terms == "" {
+		return query
+	}
+	if query == "" {
+		return terms
+	}
+	return query + " " + terms
+}
+
+// recordTime returns the created_at or updated_at time of a record. Solved
+// times are not a ticket attribute, so they are only filtered on by Zendesk.
+func (f *timeFilter) recordTime(createdAt, updatedAt time.Time) (time.Time, bool) {
+	switch f.Field {
+	case timeFieldCreatedAt:
+		return createdAt, true
+	case timeFieldUpdatedAt:
+		return updatedAt, true
+	}
+	return time.Time{}, false
+}
+
+// matches reports whether a record with the given timestamps is kept
+func (f *timeFilter) matches(createdAt, updatedAt time.Time) bool {
+	if !f.active() {
+		return true
+	}
+	t, ok := f.recordTime(createdAt, updatedAt)
+	return !ok || f.contains(t)
+}
+
+// tickets keeps the tickets within the time range
+func (f *timeFilter) tickets(tickets []zendesk.Ticket) []zendesk.Ticket {
+	if !f.active() {
+		return tickets
+	}
+	kept := make([]zendesk.Ticket, 0, len(tickets))
+	for _, ticket := range tickets {
+		if f.matches(ticket.CreatedAt, ticket.UpdatedAt) {
+			kept = append(kept, ticket)
+		}
+	}
+	return kept
+}
+
+// users keeps the users within the time range
+func (f *timeFilter) users(users []zendesk.User) []zendesk.User {
+	if !f.active() {
+		return users
+	}
+	kept := make([]zendesk.User, 0, len(users))
+	for _, user := range users {
+		if f.matches(user.CreatedAt, user.UpdatedAt) {
+			kept = append(kept, user)
+		}
+	}
+	return kept
+}
+
+// organizations keeps the organizations within the time range
+func (f *timeFilter) organizations(orgs []zendesk.Organization) []zendesk.Organization {
+	if !f.active() {
+		return orgs
+	}
+	kept := make([]zendesk.Organization, 0, len(orgs))
+	for _, org := range orgs {
+		if f.matches(org.CreatedAt, org.UpdatedAt) {
+			kept = append(kept, org)
+		}
+	}
+	return kept
+}
+
+// ticketMetrics keeps the metrics within the time range, solved_at included.
+// Metrics without a time for the field, such as unsolved tickets, are dropped.
+func (f *timeFilter) ticketMetrics(metrics []zendesk.TicketMetric) []zendesk.TicketMetric {
+	if !f.active() {
+		return metrics
+	}
+	kept := make([]zendesk.TicketMetric, 0, len(metrics))
+	for _, metric := range metrics {
+		value := &metric.CreatedAt
+		switch f.Field {
+		case timeFieldUpdatedAt:
+			value = &metric.UpdatedAt
+		case timeFieldSolvedAt:
+			value = metric.SolvedAt
+		}
+		if value != nil && f.contains(*value) {
+			kept = append(kept, metric)
+		}
+	}
+	return kept
+}
+
+// timeFilterError reports an invalid time field as a bad request
+func timeFilterError(err error) *backend.DataResponse {
+	return &backend.DataResponse{
+		Error:  err,
+		Status: backend.StatusBadRequest,
+	}
+}
@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFilter_SearchTerms(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := backend.DataQuery{TimeRange: backend.TimeRange{From: from, To: from.Add(24 * time.Hour)}}
+
+	filter, err := newTimeFilter(query, &QueryModel{TimeField: timeFieldSolvedAt}, timeFieldCreatedAt, timeFieldSolvedAt)
+	require.NoError(t, err)
+	assert.Equal(t, "status:solved solved>2024-01-01T00:00:00Z solved<2024-01-02T00:00:00Z", filter.withSearchTerms("status:solved"))
+
+	filter, err = newTimeFilter(backend.DataQuery{}, &QueryModel{}, timeFieldCreatedAt)
+	require.NoError(t, err)
+	assert.False(t, filter.active())
+	assert.Equal(t, "status:solved", filter.withSearchTerms("status:solved"))
+
+	_, err = newTimeFilter(query, &QueryModel{TimeField: timeFieldSolvedAt}, timeFieldCreatedAt, timeFieldUpdatedAt)
+	assert.EqualError(t, err, "unsupported time field: solved_at")
+}
+
+func TestQueryTickets_TimeRange(t *testing.T) {
+	var requests int
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/v2/search.json", r.URL.Path)
+		assert.Equal(t, "type:ticket status:open updated>2024-01-01T00:00:00Z updated<2024-01-01T01:00:00Z", r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"results":[
+			{"id":1,"result_type":"ticket","status":"open","created_at":"2023-12-01T00:00:00Z","updated_at":"2024-01-01T00:30:00Z"},
+			{"id":2,"result_type":"ticket","status":"open","created_at":"2023-12-01T00:00:00Z","updated_at":"2024-01-01T01:00:01Z"}],
+			"count":2}`)
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(to time.Time) *backend.DataResponse {
+		return ds.handleQuery(context.Background(), backend.DataQuery{
+			JSON:      []byte(`{"queryType":"tickets","status":"open","timeField":"updated_at"}`),
+			TimeRange: backend.TimeRange{From: from, To: to},
+		})
+	}
+
+	resp := run(from.Add(time.Hour))
+	require.NoError(t, resp.Error)
+	id, _ := resp.Frames[0].FieldByName("id")
+	require.Equal(t, 1, id.Len())
+	assert.Equal(t, int64(1), id.At(0))
+
+	// The same range is served from cache
+	run(from.Add(time.Hour))
+	assert.Equal(t, 1, requests)
+}
+
+func TestQueryTicketMetrics_SolvedAtRange(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/search.json":
+			fmt.Fprint(w, `{"results":[{"id":10,"result_type":"ticket"},{"id":11,"result_type":"ticket"},{"id":12,"result_type":"ticket"}],"count":3}`)
+		case "/api/v2/tickets/show_many.json":
+			fmt.Fprint(w, `{"tickets":[{"id":10},{"id":11},{"id":12}],"metric_sets":[
+				{"id":1,"ticket_id":10,"solved_at":"2024-01-01T00:30:00Z"},
+				{"id":2,"ticket_id":11,"solved_at":"2024-01-02T00:30:00Z"},
+				{"id":3,"ticket_id":12}]}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"ticketMetrics","timeField":"solved_at"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	ticketID, _ := resp.Frames[0].FieldByName("ticket_id")
+	require.Equal(t, 1, ticketID.Len())
+	assert.Equal(t, int64(10), ticketID.At(0))
+}
+
+func TestQueryUsers_UnsupportedTimeField(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL.Path)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"users","timeField":"solved_at"}`)})
+	require.Error(t, resp.Error)
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
@@ -0,0 +1,380 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// formatTimeSeries is the query format that buckets records over time
+const formatTimeSeries = "time_series"
+
+// Aggregations applied to the records of a time bucket or group
+const (
+	aggregationCount         = "count"
+	aggregationDistinctCount = "distinct_count"
+	aggregationSum           = "sum"
+	aggregationAvg           = "avg"
+	aggregationPercentile    = "percentile"
+)
+
+// maxTimeSeriesBuckets bounds the buckets of one series so a tiny interval
+// over a long range cannot exhaust memory
+const maxTimeSeriesBuckets = 10000
+
+// recordQueryTypes are the record queries the time_series format and
+// group-by aggregations apply to; the other query types already aggregate
+var recordQueryTypes = map[string]bool{
+	"tickets":                  true,
+	"users":                    true,
+	"organizations":            true,
+	"search":                   true,
+	"ticketMetrics":            true,
+	"view":                     true,
+	"incrementalTickets":       true,
+	"incrementalUsers":         true,
+	"incrementalOrganizations": true,
+}
+
+// timeSeriesOptions controls how record frames are bucketed into a time series
+type timeSeriesOptions struct {
+	// TimeField is the time column records are bucketed by
+	TimeField string
+	Interval  time.Duration
+	// Aggregation reduces the ValueField of a bucket; count needs no ValueField
+	Aggregation string
+	ValueField  string
+	// Percentile (0-100) is used by the percentile aggregation
+	Percentile float64
+	// SeriesBy splits records into one series per value of a column
+	SeriesBy string
+	// Wide returns one value field per series instead of a long frame
+	Wide bool
+}
+
+// parseTimeSeriesOptions reads the time series options of a query. The
+// interval defaults to the query interval, then one hour.
+func parseTimeSeriesOptions(query backend.DataQuery, model *QueryModel) (*timeSeriesOptions, error) {
+	opts := &timeSeriesOptions{
+		TimeField:   timeFieldCreatedAt,
+		Interval:    query.Interval,
+		Aggregation: aggregationCount,
+		Percentile:  95,
+		Wide:        true,
+	}
+	if model.TimeField != "" {
+		opts.TimeField = model.TimeField
+	}
+	if model.Interval != "" {
+		d, err := gtime.ParseDuration(model.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		opts.Interval = d
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	if model.Aggregation != "" {
+		opts.Aggregation = model.Aggregation
+	}
+	opts.ValueField = model.ValueField
+	opts.SeriesBy = model.SeriesBy
+	if model.Percentile != nil {
+		opts.Percentile = *model.Percentile
+	}
+	if model.FrameType != "" {
+		switch model.FrameType {
+		case "wide":
+		case "long":
+			opts.Wide = false
+		default:
+			return nil, fmt.Errorf("unknown frame type: %s", model.FrameType)
+		}
+	}
+
+	switch opts.Aggregation {
+	case aggregationCount:
+	case aggregationSum, aggregationAvg, aggregationPercentile:
+		if opts.ValueField == "" {
+			return nil, fmt.Errorf("valueField is required for %s", opts.Aggregation)
+		}
+	default:
+		return nil, fmt.Errorf("unknown aggregation: %s", opts.Aggregation)
+	}
+	if opts.Percentile < 0 || opts.Percentile > 100 {
+		return nil, fmt.Errorf("percentile must be between 0 and 100")
+	}
+	return opts, nil
+}
+
+// valueName names the aggregated value field, e.g. "count" or "avg_replies"
+func (o *timeSeriesOptions) valueName() string {
+	return aggregationName(o.Aggregation, o.ValueField, o.Percentile)
+}
+
+// aggregate reduces the values of a bucket
+func (o *timeSeriesOptions) aggregate(values []float64) *float64 {
+	return aggregateValues(o.Aggregation, values, o.Percentile)
+}
+
+// aggregationName names an aggregated value field, e.g. "count" or "avg_replies"
+func aggregationName(aggregation, valueField string, p float64) string {
+	switch aggregation {
+	case aggregationCount:
+		return aggregationCount
+	case aggregationPercentile:
+		return fmt.Sprintf("p%g_%s", p, valueField)
+	}
+	return aggregation + "_" + valueField
+}
+
+// aggregateValues reduces numeric values; count counts them and the
+// average or percentile of no values is null
+func aggregateValues(aggregation string, values []float64, p float64) *float64 {
+	var v float64
+	switch aggregation {
+	case aggregationCount:
+		v = float64(len(values))
+	case aggregationSum:
+		for _, value := range values {
+			v += value
+		}
+	case aggregationAvg:
+		v = mean(values)
+	case aggregationPercentile:
+		v = percentile(values, p)
+	}
+	if math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
+
+// timeSeriesResponse replaces the record frames of resp with a time series
+func timeSeriesResponse(query backend.DataQuery, model *QueryModel, resp *backend.DataResponse) *backend.DataResponse {
+	opts, err := parseTimeSeriesOptions(query, model)
+	if err != nil {
+		return &backend.DataResponse{
+			Error:  err,
+			Status: backend.StatusBadRequest,
+		}
+	}
+	frame, err := timeSeriesFrame(resp.Frames, query.TimeRange, opts)
+	if err != nil {
+		return &backend.DataResponse{
+			Error:  err,
+			Status: backend.StatusBadRequest,
+		}
+	}
+	carryNotices(frame, resp.Frames)
+	return &backend.DataResponse{
+		Frames: data.Frames{frame},
+	}
+}
+
+// bucketSums buckets the rows of source by timeField with timeSeriesFrame and
+// returns the bucket times with the sum of valueField per bucket, or the row
+// count without a valueField
+func bucketSums(source *data.Frame, timeField, valueField string, timeRange backend.TimeRange, interval time.Duration) ([]time.Time, []int64, error) {
+	opts := &timeSeriesOptions{
+		TimeField:   timeField,
+		Interval:    interval,
+		Aggregation: aggregationCount,
+		Wide:        true,
+	}
+	if valueField != "" {
+		opts.Aggregation = aggregationSum
+		opts.ValueField = valueField
+	}
+	frame, err := timeSeriesFrame(data.Frames{source}, timeRange, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	times := make([]time.Time, frame.Rows())
+	sums := make([]int64, frame.Rows())
+	for i := range times {
+		times[i], _ = frame.Fields[0].At(i).(time.Time)
+		if v, _ := frame.Fields[1].At(i).(*float64); v != nil {
+			sums[i] = int64(*v)
+		}
+	}
+	return times, sums, nil
+}
+
+// recordTimeAt returns the time of row i of a time or nullable time column
+func recordTimeAt(field *data.Field, i int) (time.Time, bool) {
+	switch v := field.At(i).(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false
+		}
+		return *v, true
+	}
+	return time.Time{}, false
+}
+
+// seriesLabelAt returns row i of a column as a series label
+func seriesLabelAt(field *data.Field, i int) string {
+	v, ok := field.ConcreteAt(i)
+	if !ok {
+		return "none"
+	}
+	label := fmt.Sprint(v)
+	if label == "" {
+		return "none"
+	}
+	return label
+}
+
+// timeSeriesFrame buckets the rows of the first frame holding the time column
+// into intervals of the time range, aggregating each bucket per series.
+// Buckets without records are filled so every series shares its time column.
+func timeSeriesFrame(frames data.Frames, timeRange backend.TimeRange, opts *timeSeriesOptions) (*data.Frame, error) {
+	var source *data.Frame
+	var timeColumn *data.Field
+	for _, frame := range frames {
+		if field, _ := frame.FieldByName(opts.TimeField); field != nil {
+			source, timeColumn = frame, field
+			break
+		}
+	}
+	if source == nil {
+		return nil, fmt.Errorf("no %s column to bucket by", opts.TimeField)
+	}
+	if !timeColumn.Type().Time() {
+		return nil, fmt.Errorf("%s is not a time column", opts.TimeField)
+	}
+
+	var valueColumn, seriesColumn *data.Field
+	if opts.Aggregation != aggregationCount {
+		valueColumn, _ = source.FieldByName(opts.ValueField)
+		if valueColumn == nil {
+			return nil, fmt.Errorf("unknown value field: %s", opts.ValueField)
+		}
+		if !valueColumn.Type().Numeric() {
+			return nil, fmt.Errorf("value field %s is not numeric", opts.ValueField)
+		}
+	}
+	if opts.SeriesBy != "" {
+		seriesColumn, _ = source.FieldByName(opts.SeriesBy)
+		if seriesColumn == nil {
+			return nil, fmt.Errorf("unknown series field: %s", opts.SeriesBy)
+		}
+	}
+
+	type bucketKey struct {
+		series string
+		at     time.Time
+	}
+	buckets := map[bucketKey][]float64{}
+	seriesSet := map[string]bool{}
+	var first, last time.Time
+	for i := 0; i < source.Rows(); i++ {
+		at, ok := recordTimeAt(timeColumn, i)
+		if !ok {
+			continue
+		}
+		if !timeRange.From.IsZero() && at.Before(timeRange.From) {
+			continue
+		}
+		if !timeRange.To.IsZero() && at.After(timeRange.To) {
+			continue
+		}
+
+		value := 1.0
+		if valueColumn != nil {
+			v, err := valueColumn.NullableFloatAt(i)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", opts.ValueField, err)
+			}
+			if v == nil {
+				continue
+			}
+			value = *v
+		}
+
+		series := ""
+		if seriesColumn != nil {
+			series = seriesLabelAt(seriesColumn, i)
+		}
+		seriesSet[series] = true
+		key := bucketKey{series: series, at: at.UTC().Truncate(opts.Interval)}
+		buckets[key] = append(buckets[key], value)
+		if first.IsZero() || key.at.Before(first) {
+			first = key.at
+		}
+		if key.at.After(last) {
+			last = key.at
+		}
+	}
+
+	// The time range sets the buckets; without one the records do
+	from, to := first, last
+	if !timeRange.From.IsZero() {
+		from = timeRange.From.UTC().Truncate(opts.Interval)
+	}
+	if !timeRange.To.IsZero() {
+		to = timeRange.To
+	}
+	var times []time.Time
+	if !from.IsZero() {
+		for t := from; !t.After(to); t = t.Add(opts.Interval) {
+			if len(times) == maxTimeSeriesBuckets {
+				return nil, fmt.Errorf("interval %s is too small for the time range", opts.Interval)
+			}
+			times = append(times, t)
+		}
+	}
+
+	series := make([]string, 0, len(seriesSet))
+	for s := range seriesSet {
+		series = append(series, s)
+	}
+	if len(series) == 0 && seriesColumn == nil {
+		series = append(series, "")
+	}
+	sort.Strings(series)
+
+	frame := data.NewFrame("time_series")
+	frame.Fields = append(frame.Fields, data.NewField("time", nil, []time.Time{}))
+	if seriesColumn == nil || opts.Wide {
+		for _, s := range series {
+			var labels data.Labels
+			if seriesColumn != nil {
+				labels = data.Labels{opts.SeriesBy: s}
+			}
+			frame.Fields = append(frame.Fields, data.NewField(opts.valueName(), labels, []*float64{}))
+		}
+		for _, t := range times {
+			row := []interface{}{t}
+			for _, s := range series {
+				row = append(row, opts.aggregate(buckets[bucketKey{series: s, at: t}]))
+			}
+			frame.AppendRow(row...)
+		}
+		frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesWide}
+		return frame, nil
+	}
+
+	// Long frames hold one row per time and series, sorted by time
+	frame.Fields = append(frame.Fields,
+		data.NewField(opts.SeriesBy, nil, []string{}),
+		data.NewField(opts.valueName(), nil, []*float64{}),
+	)
+	for _, t := range times {
+		for _, s := range series {
+			frame.AppendRow(t, s, opts.aggregate(buckets[bucketKey{series: s, at: t}]))
+		}
+	}
+	frame.Meta = &data.FrameMeta{Type: data.FrameTypeTimeSeriesLong}
+	return frame, nil
+}
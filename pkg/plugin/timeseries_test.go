@@ -0,0 +1,146 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ticketSeriesDatasource(t *testing.T) *Datasource {
+	return newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results":[
+			{"id":1,"result_type":"ticket","status":"open","created_at":"2024-01-01T00:10:00Z"},
+			{"id":2,"result_type":"ticket","status":"open","created_at":"2024-01-01T00:50:00Z"},
+			{"id":3,"result_type":"ticket","status":"solved","created_at":"2024-01-01T02:05:00Z"}],
+			"count":3}`)
+	}))
+}
+
+func TestTimeSeries_WideCount(t *testing.T) {
+	ds := ticketSeriesDatasource(t)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"tickets","format":"time_series","interval":"1h","seriesBy":"status"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(3 * time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Frames, 1)
+	frame := resp.Frames[0]
+	assert.Equal(t, data.FrameTypeTimeSeriesWide, frame.Meta.Type)
+	require.Len(t, frame.Fields, 3)
+	assert.Equal(t, 4, frame.Rows())
+
+	open, solved := frame.Fields[1], frame.Fields[2]
+	assert.Equal(t, data.Labels{"status": "open"}, open.Labels)
+	assert.Equal(t, data.Labels{"status": "solved"}, solved.Labels)
+	assert.Equal(t, 2.0, *open.At(0).(*float64))
+	assert.Equal(t, 0.0, *open.At(2).(*float64))
+	assert.Equal(t, 1.0, *solved.At(2).(*float64))
+}
+
+func TestTimeSeries_Long(t *testing.T) {
+	ds := ticketSeriesDatasource(t)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"tickets","format":"time_series","seriesBy":"status","frameType":"long"}`),
+		Interval:  2 * time.Hour,
+		TimeRange: backend.TimeRange{From: from, To: from.Add(3 * time.Hour)},
+	})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	assert.Equal(t, data.FrameTypeTimeSeriesLong, frame.Meta.Type)
+	assert.Equal(t, 4, frame.Rows())
+
+	var rows []string
+	for i := 0; i < frame.Rows(); i++ {
+		rows = append(rows, fmt.Sprintf("%s %s %g", frame.Fields[0].At(i).(time.Time).Format("15:04"), frame.Fields[1].At(i), *frame.Fields[2].At(i).(*float64)))
+	}
+	assert.Equal(t, []string{"00:00 open 2", "00:00 solved 0", "02:00 open 0", "02:00 solved 1"}, rows)
+}
+
+func TestTimeSeries_Percentile(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/search.json":
+			fmt.Fprint(w, `{"results":[{"id":1,"result_type":"ticket"},{"id":2,"result_type":"ticket"},{"id":3,"result_type":"ticket"},{"id":4,"result_type":"ticket"}],"count":4}`)
+		default:
+			fmt.Fprint(w, `{"tickets":[],"metric_sets":[
+				{"ticket_id":1,"replies":1,"solved_at":"2024-01-01T00:10:00Z"},
+				{"ticket_id":2,"replies":3,"solved_at":"2024-01-01T00:20:00Z"},
+				{"ticket_id":3,"replies":5,"solved_at":"2024-01-01T00:30:00Z"},
+				{"ticket_id":4,"replies":9}]}`)
+		}
+	}))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"ticketMetrics","format":"time_series","timeField":"solved_at","aggregation":"percentile","percentile":50,"valueField":"replies","interval":"1h"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(90 * time.Minute)},
+	})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	assert.Equal(t, "p50_replies", frame.Fields[1].Name)
+	require.Equal(t, 2, frame.Rows())
+	assert.Equal(t, 3.0, *frame.Fields[1].At(0).(*float64))
+	assert.Nil(t, frame.Fields[1].At(1))
+}
+
+func TestTimeSeries_TicketsSolvedAt(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/search.json":
+			assert.Contains(t, r.URL.Query().Get("query"), "solved>")
+			fmt.Fprint(w, `{"results":[
+				{"id":1,"result_type":"ticket","status":"solved","created_at":"2023-12-01T00:00:00Z"},
+				{"id":2,"result_type":"ticket","status":"solved","created_at":"2023-12-01T00:00:00Z"},
+				{"id":3,"result_type":"ticket","status":"closed","created_at":"2023-12-01T00:00:00Z"}],
+				"count":3}`)
+		case "/api/v2/tickets/show_many.json":
+			assert.Equal(t, "1,2,3", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"tickets":[],"metric_sets":[
+				{"ticket_id":1,"solved_at":"2024-01-01T00:10:00Z"},
+				{"ticket_id":2,"solved_at":"2024-01-01T01:20:00Z"},
+				{"ticket_id":3,"solved_at":"2024-01-01T01:25:00Z"}]}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{
+		JSON:      []byte(`{"queryType":"tickets","format":"time_series","timeField":"solved_at","interval":"1h"}`),
+		TimeRange: backend.TimeRange{From: from, To: from.Add(90 * time.Minute)},
+	})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	require.Equal(t, 2, frame.Rows())
+	assert.Equal(t, 1.0, *frame.Fields[1].At(0).(*float64))
+	assert.Equal(t, 2.0, *frame.Fields[1].At(1).(*float64))
+}
+
+func TestTimeSeries_InvalidOptions(t *testing.T) {
+	ds := ticketSeriesDatasource(t)
+	for _, tc := range []struct {
+		json string
+		err  string
+	}{
+		{`{"queryType":"tickets","format":"time_series","aggregation":"avg"}`, "valueField is required for avg"},
+		{`{"queryType":"tickets","format":"time_series","aggregation":"sum","valueField":"status"}`, "value field status is not numeric"},
+		{`{"queryType":"tickets","format":"time_series","aggregation":"median"}`, "unknown aggregation: median"},
+		{`{"queryType":"tickets","format":"time_series","interval":"1s"}`, "interval 1s is too small for the time range"},
+	} {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		resp := ds.handleQuery(context.Background(), backend.DataQuery{
+			JSON:      []byte(tc.json),
+			TimeRange: backend.TimeRange{From: from, To: from.Add(24 * time.Hour)},
+		})
+		require.Error(t, resp.Error, tc.json)
+		assert.EqualError(t, resp.Error, tc.err, tc.json)
+		assert.Equal(t, backend.StatusBadRequest, resp.Status, tc.json)
+	}
+}
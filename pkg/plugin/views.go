@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/circleyu/zendesk-datasource/pkg/cache"
+	"github.com/circleyu/zendesk-datasource/pkg/zendesk"
+)
+
+// viewsCacheKey caches the views available to the configured user
+const viewsCacheKey = "views"
+
+// listViews returns the views of the account, from cache when possible
+func (ds *Datasource) listViews(ctx context.Context) ([]zendesk.View, error) {
+	if cached, found := ds.cacheManager.Get(viewsCacheKey); found {
+		if views, ok := cached.([]zendesk.View); ok {
+			return views, nil
+		}
+	}
+
+	views, err := ds.zendeskClient.ListViews(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ds.cacheManager.Set(viewsCacheKey, views, cache.DefaultConfig().DefaultTTL)
+	return views, nil
+}
+
+// queryView handles view queries: viewId returns the tickets in a view,
+// filtered by the time range, and viewIds returns the live ticket count of
+// each view
+func (ds *Datasource) queryView(ctx context.Context, query backend.DataQuery, model *QueryModel) *backend.DataResponse {
+	if len(model.ViewIDs) > 0 {
+		for _, id := range model.ViewIDs {
+			if id <= 0 {
+				return &backend.DataResponse{
+					Error:  fmt.Errorf("invalid view id: %d", id),
+					Status: backend.StatusBadRequest,
+				}
+			}
+		}
+		return ds.queryViewCounts(ctx, model.ViewIDs)
+	}
+
+	viewID := model.ViewID
+	if viewID <= 0 {
+		return &backend.DataResponse{
+			Error:  fmt.Errorf("viewId or viewIds is required"),
+			Status: backend.StatusBadRequest,
+		}
+	}
+
+	filter, err := newTimeFilter(query, model, timeFieldCreatedAt, timeFieldUpdatedAt)
+	if err != nil {
+		return timeFilterError(err)
+	}
+
+	// Check cache first
+	cacheKey := fmt.Sprintf("view:%d:%s", viewID, filter.key())
+	if cached, found := ds.cacheManager.Get(cacheKey); found {
+		if tickets, ok := cached.([]zendesk.Ticket); ok {
+			return ds.ticketsResponse(ctx, tickets, nil, model)
+		}
+	}
+
+	// Fetch from API
+	tickets, err := ds.zendeskClient.ListViewTickets(ctx, viewID, maxQueryRows)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch view tickets: %w", err),
+		}
+	}
+
+	tickets = filter.tickets(tickets)
+
+	// Cache the result
+	ds.cacheManager.Set(cacheKey, tickets, cache.DefaultConfig().DefaultTTL)
+
+	return ds.ticketsResponse(ctx, tickets, nil, model)
+}
+
+// queryViewCounts returns the ticket counts of views. Counts are not cached:
+// Zendesk already caches them and wallboards expect them live.
+func (ds *Datasource) queryViewCounts(ctx context.Context, viewIDs []int64) *backend.DataResponse {
+	counts, err := ds.zendeskClient.CountViews(ctx, viewIDs)
+	if err != nil {
+		return &backend.DataResponse{
+			Error: fmt.Errorf("failed to fetch view counts: %w", err),
+		}
+	}
+
+	// View titles are a convenience, counts are still returned without them
+	titles := map[int64]string{}
+	if views, err := ds.listViews(ctx); err == nil {
+		for _, view := range views {
+			titles[view.ID] = view.Title
+		}
+	}
+
+	return ds.viewCountsToDataFrame(counts, titles)
+}
+
+// viewCountsToDataFrame converts view counts to Grafana DataFrame
+func (ds *Datasource) viewCountsToDataFrame(counts []zendesk.ViewCount, titles map[int64]string) *backend.DataResponse {
+	frame := data.NewFrame("view_counts")
+	frame.Fields = append(frame.Fields,
+		data.NewField("view_id", nil, []int64{}),
+		data.NewField("title", nil, []string{}),
+		data.NewField("count", nil, []*int64{}),
+		data.NewField("fresh", nil, []bool{}),
+	)
+
+	for _, count := range counts {
+		frame.AppendRow(
+			count.ViewID,
+			titles[count.ViewID],
+			count.Value,
+			count.Fresh,
+		)
+	}
+
+	return &backend.DataResponse{
+		Frames: data.Frames{frame},
+	}
+}
+
+// handleViews lists the active views as dashboard variable options, in view order
+func (ds *Datasource) handleViews(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	views, err := ds.listViews(ctx)
+	if err != nil {
+		return sender.Send(&backend.CallResourceResponse{
+			Status: 500,
+			Body:   []byte(fmt.Sprintf(`{"error":"Failed to fetch views: %v"}`, err)),
+		})
+	}
+
+	options := make([]variableOption, 0, len(views))
+	for _, view := range views {
+		if view.Active {
+			options = append(options, variableOption{Text: view.Title, Value: view.ID})
+		}
+	}
+	return sendJSON(sender, options)
+}
@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryView_Tickets(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/views/7/tickets.json", r.URL.Path)
+		fmt.Fprint(w, `{"tickets":[{"id":1,"subject":"Printer","status":"open"}],"meta":{"has_more":false}}`)
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"view","viewId":7}`)})
+	require.NoError(t, resp.Error)
+	assert.Equal(t, "tickets", resp.Frames[0].Name)
+	assert.Equal(t, 1, resp.Frames[0].Rows())
+}
+
+func TestQueryView_Counts(t *testing.T) {
+	ds := newTestDatasource(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/views/count_many.json":
+			assert.Equal(t, "7,8", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"view_counts":[{"view_id":7,"value":12,"fresh":true},{"view_id":8,"value":null,"fresh":false}]}`)
+		case "/api/v2/views.json":
+			fmt.Fprint(w, `{"views":[{"id":7,"title":"Unassigned","active":true}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"view","viewIds":[7,8]}`)})
+	require.NoError(t, resp.Error)
+	frame := resp.Frames[0]
+	require.Equal(t, 2, frame.Rows())
+
+	title, _ := frame.FieldByName("title")
+	count, _ := frame.FieldByName("count")
+	assert.Equal(t, "Unassigned", title.At(0))
+	assert.Equal(t, int64(12), *count.At(0).(*int64))
+	assert.Nil(t, count.At(1))
+}
+
+func TestQueryView_RequiresView(t *testing.T) {
+	ds := newTestDatasource(t, http.NotFoundHandler())
+
+	resp := ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"view"}`)})
+	assert.Error(t, resp.Error)
+
+	resp = ds.handleQuery(context.Background(), backend.DataQuery{JSON: []byte(`{"queryType":"view","viewIds":[3,-1]}`)})
+	assert.EqualError(t, resp.Error, "invalid view id: -1")
+	assert.Equal(t, backend.StatusBadRequest, resp.Status)
+}
@@ -0,0 +1,263 @@
+package zendesk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Ticket change event types
+const (
+	ChangeEventCreate  = "Create"
+	ChangeEventChange  = "Change"
+	ChangeEventComment = "Comment"
+)
+
+// ChangeValue is a ticket field value as recorded in audits and ticket
+// events. Numbers and booleans are kept in their JSON form and lists (such as
+// tags) are joined with spaces.
+type ChangeValue string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *ChangeValue) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	switch {
+	case bytes.Equal(b, []byte("null")):
+		*v = ""
+	case len(b) > 0 && b[0] == '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*v = ChangeValue(s)
+	case len(b) > 0 && b[0] == '[':
+		var items []ChangeValue
+		if err := json.Unmarshal(b, &items); err != nil {
+			return err
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = string(item)
+		}
+		*v = ChangeValue(strings.Join(parts, " "))
+	default:
+		*v = ChangeValue(b)
+	}
+	return nil
+}
+
+// AuditEvent represents a single event of a ticket audit
+type AuditEvent struct {
+	ID            int64       `json:"id"`
+	Type          string      `json:"type"`
+	FieldName     string      `json:"field_name,omitempty"`
+	Value         ChangeValue `json:"value,omitempty"`
+	PreviousValue ChangeValue `json:"previous_value,omitempty"`
+	Body          string      `json:"body,omitempty"`
+	Public        *bool       `json:"public,omitempty"`
+	AuthorID      *int64      `json:"author_id,omitempty"`
+}
+
+// TicketAudit represents a set of changes made to a ticket at once
+type TicketAudit struct {
+	ID        int64        `json:"id"`
+	TicketID  int64        `json:"ticket_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	AuthorID  int64        `json:"author_id"`
+	Via       *Via         `json:"via,omitempty"`
+	Events    []AuditEvent `json:"events"`
+}
+
+// TicketAuditsResponse represents the response from ticket audits API
+type TicketAuditsResponse struct {
+	Audits []TicketAudit `json:"audits"`
+	Meta   *Meta         `json:"meta,omitempty"`
+	Links  *Links        `json:"links,omitempty"`
+}
+
+// TicketChildEvent is a field change within an incremental ticket event.
+// The export names the changed field by its key, e.g. {"status":"open"}; it is
+// decoded into FieldName and Value.
+type TicketChildEvent struct {
+	ID            int64
+	EventType     string
+	FieldName     string
+	Value         ChangeValue
+	PreviousValue ChangeValue
+}
+
+// childEventMetaKeys are the keys of a child event that do not name a field
+var childEventMetaKeys = map[string]bool{
+	"id": true, "via": true, "via_reference_id": true, "event_type": true,
+	"previous_value": true, "type": true, "audit_id": true, "created_at": true,
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (e *TicketChildEvent) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*e = TicketChildEvent{}
+	if id, ok := raw["id"]; ok {
+		json.Unmarshal(id, &e.ID)
+	}
+	if eventType, ok := raw["event_type"]; ok {
+		json.Unmarshal(eventType, &e.EventType)
+	}
+	if previous, ok := raw["previous_value"]; ok {
+		if err := json.Unmarshal(previous, &e.PreviousValue); err != nil {
+			return err
+		}
+	}
+
+	if field, ok := raw["field_name"]; ok {
+		json.Unmarshal(field, &e.FieldName)
+		if value, ok := raw["value"]; ok {
+			return json.Unmarshal(value, &e.Value)
+		}
+		return nil
+	}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		if !childEventMetaKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	e.FieldName = keys[0]
+	return json.Unmarshal(raw[keys[0]], &e.Value)
+}
+
+// TicketEvent represents an entry of the incremental ticket event export
+type TicketEvent struct {
+	ID          int64              `json:"id"`
+	TicketID    int64              `json:"ticket_id"`
+	Timestamp   int64              `json:"timestamp"`
+	CreatedAt   string             `json:"created_at"`
+	UpdaterID   int64              `json:"updater_id"`
+	EventType   string             `json:"event_type"`
+	ChildEvents []TicketChildEvent `json:"child_events"`
+}
+
+// IncrementalTicketEventsResponse represents a page of the ticket event export
+type IncrementalTicketEventsResponse struct {
+	TicketEvents []TicketEvent `json:"ticket_events"`
+	NextPage     *string       `json:"next_page,omitempty"`
+	EndTime      int64         `json:"end_time"`
+	Count        int           `json:"count"`
+	EndOfStream  bool          `json:"end_of_stream"`
+}
+
+// TicketChange is a typed field change of a ticket, from an audit or a ticket event
+type TicketChange struct {
+	TicketID      int64
+	At            time.Time
+	Type          string
+	FieldName     string
+	Value         string
+	PreviousValue string
+}
+
+// Changes returns the field changes recorded by the audit
+func (a TicketAudit) Changes() []TicketChange {
+	var changes []TicketChange
+	for _, event := range a.Events {
+		if event.Type != ChangeEventCreate && event.Type != ChangeEventChange {
+			continue
+		}
+		changes = append(changes, TicketChange{
+			TicketID:      a.TicketID,
+			At:            a.CreatedAt,
+			Type:          event.Type,
+			FieldName:     event.FieldName,
+			Value:         string(event.Value),
+			PreviousValue: string(event.PreviousValue),
+		})
+	}
+	return changes
+}
+
+// Changes returns the field changes recorded by the ticket event
+func (e TicketEvent) Changes() []TicketChange {
+	at := time.Unix(e.Timestamp, 0).UTC()
+	var changes []TicketChange
+	for _, child := range e.ChildEvents {
+		if child.FieldName == "" {
+			continue
+		}
+		if child.EventType != ChangeEventCreate && child.EventType != ChangeEventChange {
+			continue
+		}
+		changes = append(changes, TicketChange{
+			TicketID:      e.TicketID,
+			At:            at,
+			Type:          child.EventType,
+			FieldName:     child.FieldName,
+			Value:         string(child.Value),
+			PreviousValue: string(child.PreviousValue),
+		})
+	}
+	return changes
+}
+
+// ListTicketAudits retrieves every audit of a ticket, oldest first
+func (c *Client) ListTicketAudits(ctx context.Context, ticketID int64) ([]TicketAudit, error) {
+	var audits []TicketAudit
+	path := fmt.Sprintf("/tickets/%d/audits.json", ticketID)
+	err := c.walkPages(ctx, path, nil, 0, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page TicketAuditsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		audits = append(audits, page.Audits...)
+		return &pageResult{Rows: len(page.Audits), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// IncrementalTicketEvents walks the time-based ticket event export, calling
+// fn once per page, and returns the checkpoint to resume from
+func (c *Client) IncrementalTicketEvents(ctx context.Context, opts IncrementalOptions, fn func(events []TicketEvent) error) (*IncrementalCheckpoint, error) {
+	opts.Cursor = ""
+	endpoint := incrementalEndpoint("/incremental/ticket_events.json", opts)
+	return c.walkIncremental(ctx, endpoint, opts, func(body io.Reader, remaining int) (*incrementalPage, error) {
+		var page IncrementalTicketEventsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		// An empty page also marks the end of this export
+		result := &incrementalPage{
+			EndOfStream: page.EndOfStream || len(page.TicketEvents) == 0,
+			EndTime:     page.EndTime,
+		}
+		if page.NextPage != nil {
+			result.NextURL = *page.NextPage
+		}
+
+		rows := make([]TicketEvent, 0, len(page.TicketEvents))
+		for _, event := range page.TicketEvents {
+			if !opts.EndTime.IsZero() && event.Timestamp > opts.EndTime.Unix() {
+				result.PastEnd = true
+				continue
+			}
+			rows = append(rows, event)
+		}
+		rows = rows[:trimRows(len(rows), remaining)]
+		result.Rows = len(rows)
+		return result, fn(rows)
+	})
+}
@@ -0,0 +1,94 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketChildEvent_UnmarshalJSON(t *testing.T) {
+	var events []TicketChildEvent
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id":1,"event_type":"Change","status":"solved","previous_value":"open","via":"Web form"},
+		{"id":2,"event_type":"Change","tags":["vip","billing"],"previous_value":null},
+		{"id":3,"event_type":"Create","field_name":"priority","value":"high"},
+		{"id":4,"event_type":"Comment","comment_public":true}
+	]`), &events))
+
+	require.Len(t, events, 4)
+	assert.Equal(t, "status", events[0].FieldName)
+	assert.Equal(t, ChangeValue("solved"), events[0].Value)
+	assert.Equal(t, ChangeValue("open"), events[0].PreviousValue)
+	assert.Equal(t, "tags", events[1].FieldName)
+	assert.Equal(t, ChangeValue("vip billing"), events[1].Value)
+	assert.Equal(t, ChangeValue(""), events[1].PreviousValue)
+	assert.Equal(t, "priority", events[2].FieldName)
+	assert.Equal(t, ChangeValue("high"), events[2].Value)
+	assert.Equal(t, ChangeValue("true"), events[3].Value)
+}
+
+func TestTicketEvent_Changes(t *testing.T) {
+	event := TicketEvent{
+		TicketID:  7,
+		Timestamp: 1704096000,
+		ChildEvents: []TicketChildEvent{
+			{EventType: ChangeEventChange, FieldName: "status", Value: "pending", PreviousValue: "open"},
+			{EventType: ChangeEventComment, FieldName: "comment_public", Value: "true"},
+		},
+	}
+
+	changes := event.Changes()
+	require.Len(t, changes, 1)
+	assert.Equal(t, int64(7), changes[0].TicketID)
+	assert.Equal(t, time.Unix(1704096000, 0).UTC(), changes[0].At)
+	assert.Equal(t, "pending", changes[0].Value)
+	assert.Equal(t, "open", changes[0].PreviousValue)
+}
+
+func TestListTicketAudits(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets/5/audits.json", r.URL.Path)
+		fmt.Fprint(w, `{"audits":[{"id":1,"ticket_id":5,"created_at":"2024-01-01T08:00:00Z","events":[
+			{"id":10,"type":"Create","field_name":"status","value":"new"},
+			{"id":11,"type":"Comment","body":"Hello","public":true}
+		]}],"meta":{"has_more":false}}`)
+	}))
+
+	audits, err := client.ListTicketAudits(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+
+	changes := audits[0].Changes()
+	require.Len(t, changes, 1)
+	assert.Equal(t, ChangeEventCreate, changes[0].Type)
+	assert.Equal(t, "new", changes[0].Value)
+}
+
+func TestIncrementalTicketEvents_EmptyPageEndsExport(t *testing.T) {
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/incremental/ticket_events.json", r.URL.Path)
+		calls++
+		if calls == 1 {
+			fmt.Fprintf(w, `{"ticket_events":[{"id":1,"ticket_id":2,"timestamp":1704096000,"event_type":"Audit","child_events":[{"event_type":"Change","status":"open","previous_value":"new"}]}],"next_page":"http://%s/api/v2/incremental/ticket_events.json?start_time=1704096001","end_time":1704096001}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"ticket_events":[],"end_time":1704096001}`)
+	}))
+
+	var events []TicketEvent
+	_, err := client.IncrementalTicketEvents(context.Background(), IncrementalOptions{StartTime: time.Unix(1704000000, 0)}, func(page []TicketEvent) error {
+		events = append(events, page...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, "status", events[0].ChildEvents[0].FieldName)
+}
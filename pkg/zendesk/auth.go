@@ -0,0 +1,200 @@
+package zendesk
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Authenticator sets credentials on outgoing Zendesk API requests
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// RenewableAuthenticator is an Authenticator whose credentials can be renewed
+// once Zendesk rejects them, e.g. an expired OAuth access token. The client
+// invalidates them and retries a request rejected with 401 once.
+type RenewableAuthenticator interface {
+	Authenticator
+	// Invalidate drops the credentials set on req, if they are still current
+	Invalidate(req *http.Request)
+}
+
+// APITokenAuth authenticates with an agent email and API token
+type APITokenAuth struct {
+	Email    string
+	APIToken string
+}
+
+// Authenticate implements Authenticator
+func (a *APITokenAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	credentials := fmt.Sprintf("%s/token:%s", a.Email, a.APIToken)
+	encoded := base64.StdEncoding.EncodeToString([]byte(credentials))
+	req.Header.Set("Authorization", fmt.Sprintf("Basic %s", encoded))
+	return nil
+}
+
+// BearerTokenAuth authenticates with a pre-issued OAuth access token
+type BearerTokenAuth struct {
+	AccessToken string
+}
+
+// Authenticate implements Authenticator
+func (a *BearerTokenAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.AccessToken))
+	return nil
+}
+
+// tokenExpiryMargin renews client credentials tokens this long before they expire
+const tokenExpiryMargin = time.Minute
+
+// ClientCredentialsAuth exchanges OAuth client credentials for an access
+// token and caches it until shortly before it expires, or until Zendesk
+// rejects it when the token endpoint gives no expiry. Concurrent requests
+// share a single exchange.
+type ClientCredentialsAuth struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+	HTTPClient   *http.Client
+
+	mu       sync.Mutex
+	token    string
+	expiry   time.Time
+	inflight *tokenExchange
+}
+
+// tokenExchange is a token exchange in flight, shared by the requests waiting for it
+type tokenExchange struct {
+	done   chan struct{}
+	token  string
+	expiry time.Time
+	err    error
+}
+
+// tokenResponse represents the response from an OAuth token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in,omitempty"`
+}
+
+// DefaultTokenURL returns the Zendesk OAuth token endpoint of a subdomain
+func DefaultTokenURL(subdomain string) string {
+	return fmt.Sprintf("https://%s.zendesk.com/oauth/tokens", subdomain)
+}
+
+// Authenticate implements Authenticator
+func (a *ClientCredentialsAuth) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	return nil
+}
+
+// Invalidate implements RenewableAuthenticator. A token renewed since req
+// was authenticated is kept.
+func (a *ClientCredentialsAuth) Invalidate(req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && req.Header.Get("Authorization") == fmt.Sprintf("Bearer %s", a.token) {
+		a.token = ""
+		a.expiry = time.Time{}
+	}
+}
+
+// accessToken returns the cached token, waiting for an exchange when it is
+// missing or expiring. The exchange outlives a caller giving up on it, so
+// the token is cached for the next request.
+func (a *ClientCredentialsAuth) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	if a.token != "" && (a.expiry.IsZero() || time.Now().Add(tokenExpiryMargin).Before(a.expiry)) {
+		token := a.token
+		a.mu.Unlock()
+		return token, nil
+	}
+	exchange := a.inflight
+	if exchange == nil {
+		exchange = &tokenExchange{done: make(chan struct{})}
+		a.inflight = exchange
+		go a.runExchange(context.WithoutCancel(ctx), exchange)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-exchange.done:
+		return exchange.token, exchange.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// runExchange performs a token exchange and caches its token
+func (a *ClientCredentialsAuth) runExchange(ctx context.Context, exchange *tokenExchange) {
+	exchange.token, exchange.expiry, exchange.err = a.exchangeToken(ctx)
+
+	a.mu.Lock()
+	if exchange.err == nil {
+		a.token = exchange.token
+		a.expiry = exchange.expiry
+	}
+	a.inflight = nil
+	a.mu.Unlock()
+	close(exchange.done)
+}
+
+// exchangeToken requests an access token from the token endpoint. A zero
+// expiry means the endpoint gave none.
+func (a *ClientCredentialsAuth) exchangeToken(ctx context.Context) (string, time.Time, error) {
+	body, err := json.Marshal(map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     a.ClientID,
+		"client_secret": a.ClientSecret,
+		"scope":         a.Scope,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode token request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.TokenURL, bytes.NewReader(body))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := a.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return "", time.Time{}, fmt.Errorf("token exchange failed: %w", newAPIError(resp))
+	}
+
+	var result tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("token exchange returned no access token")
+	}
+
+	var expiry time.Time
+	if result.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return result.AccessToken, expiry, nil
+}
@@ -0,0 +1,147 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPITokenAuth(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://test.zendesk.com/api/v2/users/me.json", nil)
+	auth := &APITokenAuth{Email: "test@example.com", APIToken: "secret"}
+
+	require.NoError(t, auth.Authenticate(context.Background(), req))
+	user, pass, ok := req.BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "test@example.com/token", user)
+	assert.Equal(t, "secret", pass)
+}
+
+func TestClientCredentialsAuth_CachesToken(t *testing.T) {
+	exchanges := 0
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/tokens":
+			exchanges++
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "client_credentials", body["grant_type"])
+			assert.Equal(t, "grafana", body["client_id"])
+			assert.Equal(t, "read", body["scope"])
+			fmt.Fprint(w, `{"access_token":"abc","token_type":"bearer","expires_in":3600}`)
+		default:
+			assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+			fmt.Fprint(w, `{"user":{"id":1}}`)
+		}
+	}))
+	client.auth = &ClientCredentialsAuth{
+		TokenURL:     server.URL + "/oauth/tokens",
+		ClientID:     "grafana",
+		ClientSecret: "secret",
+		Scope:        "read",
+	}
+
+	require.NoError(t, client.TestConnection(context.Background()))
+	require.NoError(t, client.TestConnection(context.Background()))
+	assert.Equal(t, 1, exchanges)
+}
+
+func TestClientCredentialsAuth_ExchangeFailure(t *testing.T) {
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"invalid_client"}`)
+	}))
+	client.auth = &ClientCredentialsAuth{TokenURL: server.URL + "/oauth/tokens"}
+
+	err := client.TestConnection(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_client")
+}
+
+func TestClientCredentialsAuth_RenewsRejectedToken(t *testing.T) {
+	exchanges := 0
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/tokens":
+			// Tokens without expires_in are kept until Zendesk rejects them
+			exchanges++
+			fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer"}`, exchanges)
+		default:
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":"invalid_token"}`)
+				return
+			}
+			fmt.Fprint(w, `{"user":{"id":1}}`)
+		}
+	}))
+	client.retryPolicy = RetryPolicy{MaxAttempts: 1}
+	client.auth = &ClientCredentialsAuth{TokenURL: server.URL + "/oauth/tokens"}
+
+	require.NoError(t, client.TestConnection(context.Background()))
+	require.NoError(t, client.TestConnection(context.Background()))
+	assert.Equal(t, 2, exchanges)
+}
+
+func TestClientCredentialsAuth_RenewsOnce(t *testing.T) {
+	var exchanges, calls int
+	client, server := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/tokens":
+			exchanges++
+			fmt.Fprint(w, `{"access_token":"abc","token_type":"bearer"}`)
+		default:
+			calls++
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	client.auth = &ClientCredentialsAuth{TokenURL: server.URL + "/oauth/tokens"}
+
+	assert.ErrorIs(t, client.TestConnection(context.Background()), ErrUnauthorized)
+	assert.Equal(t, 2, exchanges)
+	assert.Equal(t, 2, calls)
+}
+
+func TestClientCredentialsAuth_SharesExchange(t *testing.T) {
+	var exchanges int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&exchanges, 1)
+		<-release
+		fmt.Fprint(w, `{"access_token":"abc","token_type":"bearer","expires_in":3600}`)
+	}))
+	t.Cleanup(server.Close)
+	auth := &ClientCredentialsAuth{TokenURL: server.URL}
+
+	// A caller giving up does not wait for the exchange in flight
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := auth.accessToken(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = auth.accessToken(context.Background())
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+
+	for _, token := range tokens {
+		assert.Equal(t, "abc", token)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&exchanges))
+}
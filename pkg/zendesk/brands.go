@@ -0,0 +1,46 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Brand represents a Zendesk brand
+type Brand struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Name      string    `json:"name"`
+	Subdomain string    `json:"subdomain"`
+	Active    bool      `json:"active"`
+	Default   bool      `json:"default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BrandsResponse represents the response from brands API
+type BrandsResponse struct {
+	Brands []Brand `json:"brands"`
+	Meta   *Meta   `json:"meta,omitempty"`
+	Links  *Links  `json:"links,omitempty"`
+}
+
+// ListBrands retrieves every brand across every page, up to maxRows brands (0 means no cap)
+func (c *Client) ListBrands(ctx context.Context, maxRows int) ([]Brand, error) {
+	var brands []Brand
+	err := c.walkPages(ctx, "/brands.json", nil, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page BrandsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Brands[:trimRows(len(page.Brands), remaining)]
+		brands = append(brands, rows...)
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
@@ -1,84 +1,207 @@
 package zendesk
 
 import (
-	"encoding/base64"
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// DefaultTimeout is the default timeout of a single attempt of a Zendesk API request
+const DefaultTimeout = 30 * time.Second
+
 // Client represents a Zendesk API client
 type Client struct {
-	baseURL    string
-	email      string
-	apiToken   string
-	httpClient *http.Client
-}
-
-// NewClient creates a new Zendesk API client
-func NewClient(subdomain, email, apiToken string) *Client {
-	return &Client{
-		baseURL:  fmt.Sprintf("https://%s.zendesk.com/api/v2", subdomain),
-		email:    email,
-		apiToken: apiToken,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	baseURL     string
+	auth        Authenticator
+	transport   http.RoundTripper
+	timeout     time.Duration
+	httpClient  *http.Client
+	rateLimiter *RateLimiter
+	retryPolicy RetryPolicy
+}
+
+// ClientOption configures optional client behavior
+type ClientOption func(*Client)
+
+// WithRetryPolicy sets the policy used to retry transient failures
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retryPolicy = policy
 	}
 }
 
-// getAuthHeader returns the Authorization header value
-func (c *Client) getAuthHeader() string {
-	credentials := fmt.Sprintf("%s/token:%s", c.email, c.apiToken)
-	encoded := base64.StdEncoding.EncodeToString([]byte(credentials))
-	return fmt.Sprintf("Basic %s", encoded)
+// WithAuthenticator replaces the default API token authentication
+func WithAuthenticator(auth Authenticator) ClientOption {
+	return func(c *Client) {
+		c.auth = auth
+	}
 }
 
-// request performs an HTTP request to the Zendesk API
-func (c *Client) request(method, endpoint string, body io.Reader) (*http.Response, error) {
-	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// WithBaseURL overrides the https://{subdomain}.zendesk.com/api/v2 API base
+// URL, e.g. for host-mapped domains or test servers
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
 	}
+}
 
-	req.Header.Set("Authorization", c.getAuthHeader())
-	req.Header.Set("Content-Type", "application/json")
+// WithTransport sets the HTTP transport used beneath rate limiting, e.g. to
+// configure proxies, CA bundles or client certificates
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// WithTimeout sets the timeout of a single attempt of an API request; waits
+// for the rate limit and between retries do not count towards it
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
 	}
+}
 
-	if resp.StatusCode >= 400 {
-		defer resp.Body.Close()
-		var errorResp ErrorResponse
-		if err := json.NewDecoder(resp.Body).Decode(&errorResp); err == nil {
-			return nil, fmt.Errorf("API error: %s", errorResp.Error)
-		}
-		return nil, fmt.Errorf("HTTP error: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+// NewClient creates a new Zendesk API client that authenticates with an
+// agent email and API token unless WithAuthenticator is given
+func NewClient(subdomain, email, apiToken string, opts ...ClientOption) *Client {
+	client := &Client{
+		baseURL:     fmt.Sprintf("https://%s.zendesk.com/api/v2", subdomain),
+		auth:        &APITokenAuth{Email: email, APIToken: apiToken},
+		transport:   http.DefaultTransport,
+		timeout:     DefaultTimeout,
+		retryPolicy: DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
 
+	// Clients of the same account share one rate limit budget. The timeout
+	// bounds each attempt beneath rate limiting, so rate limit waits and 429
+	// retries do not count towards it.
+	client.rateLimiter = rateLimiterFor(client.baseURL)
+	client.httpClient = &http.Client{
+		Transport: &rateLimitTransport{
+			base:    &timeoutTransport{base: client.transport, timeout: client.timeout},
+			limiter: client.rateLimiter,
+		},
+	}
+	return client
+}
+
+// timeoutTransport bounds each round trip, reading the response body
+// included, by a timeout
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 	return resp, nil
 }
 
-// GetTickets retrieves tickets from Zendesk
-func (c *Client) GetTickets(params map[string]string) (*TicketsResponse, error) {
-	endpoint := "/tickets.json"
-	if len(params) > 0 {
-		query := ""
-		for k, v := range params {
-			if query != "" {
-				query += "&"
+// cancelOnClose releases the context of a round trip once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// request performs an HTTP request to the Zendesk API, retrying transient
+// failures according to the client retry policy
+func (c *Client) request(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
+	url := fmt.Sprintf("%s%s", c.baseURL, endpoint)
+
+	// Buffer the body so it can be replayed on retries
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	attempts := c.retryPolicy.attemptsFor(method)
+	renewed := false
+	for attempt := 1; ; attempt++ {
+		var reqBody io.Reader
+		if payload != nil {
+			reqBody = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if err := c.auth.Authenticate(ctx, req); err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req)
+		retry := attempt < attempts && ctx.Err() == nil
+		if err != nil {
+			if retry && isRetryableError(err) {
+				if err := sleepContext(ctx, c.retryPolicy.backoff(attempt)); err != nil {
+					return nil, fmt.Errorf("failed to execute request: %w", err)
+				}
+				continue
 			}
-			query += fmt.Sprintf("%s=%s", k, v)
+			return nil, fmt.Errorf("failed to execute request: %w", err)
 		}
-		endpoint += "?" + query
+
+		if retry && isRetryableStatus(resp.StatusCode) {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if err := sleepContext(ctx, c.retryPolicy.backoff(attempt)); err != nil {
+				return nil, fmt.Errorf("failed to execute request: %w", err)
+			}
+			continue
+		}
+
+		// Rejected credentials are renewed once, e.g. an OAuth token revoked
+		// or expired before its announced expiry
+		if renewable, ok := c.auth.(RenewableAuthenticator); ok && resp.StatusCode == http.StatusUnauthorized && !renewed {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			renewable.Invalidate(req)
+			renewed = true
+			continue
+		}
+
+		if resp.StatusCode >= 400 {
+			defer resp.Body.Close()
+			return nil, newAPIError(resp)
+		}
+
+		return resp, nil
 	}
+}
 
-	resp, err := c.request("GET", endpoint, nil)
+// GetTickets retrieves tickets from Zendesk
+func (c *Client) GetTickets(ctx context.Context, params map[string]string) (*TicketsResponse, error) {
+	resp, err := c.request(ctx, "GET", buildEndpoint("/tickets.json", params), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -93,20 +216,8 @@ func (c *Client) GetTickets(params map[string]string) (*TicketsResponse, error)
 }
 
 // GetUsers retrieves users from Zendesk
-func (c *Client) GetUsers(params map[string]string) (*UsersResponse, error) {
-	endpoint := "/users.json"
-	if len(params) > 0 {
-		query := ""
-		for k, v := range params {
-			if query != "" {
-				query += "&"
-			}
-			query += fmt.Sprintf("%s=%s", k, v)
-		}
-		endpoint += "?" + query
-	}
-
-	resp, err := c.request("GET", endpoint, nil)
+func (c *Client) GetUsers(ctx context.Context, params map[string]string) (*UsersResponse, error) {
+	resp, err := c.request(ctx, "GET", buildEndpoint("/users.json", params), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -121,20 +232,8 @@ func (c *Client) GetUsers(params map[string]string) (*UsersResponse, error) {
 }
 
 // GetOrganizations retrieves organizations from Zendesk
-func (c *Client) GetOrganizations(params map[string]string) (*OrganizationsResponse, error) {
-	endpoint := "/organizations.json"
-	if len(params) > 0 {
-		query := ""
-		for k, v := range params {
-			if query != "" {
-				query += "&"
-			}
-			query += fmt.Sprintf("%s=%s", k, v)
-		}
-		endpoint += "?" + query
-	}
-
-	resp, err := c.request("GET", endpoint, nil)
+func (c *Client) GetOrganizations(ctx context.Context, params map[string]string) (*OrganizationsResponse, error) {
+	resp, err := c.request(ctx, "GET", buildEndpoint("/organizations.json", params), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -149,8 +248,8 @@ func (c *Client) GetOrganizations(params map[string]string) (*OrganizationsRespo
 }
 
 // TestConnection tests the connection to Zendesk API
-func (c *Client) TestConnection() error {
-	resp, err := c.request("GET", "/users/me.json", nil)
+func (c *Client) TestConnection(ctx context.Context) error {
+	resp, err := c.request(ctx, "GET", "/users/me.json", nil)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,18 @@
+package zendesk
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client pointed at a test server running handler,
+// with a rate limiter of its own
+func newTestClient(t *testing.T, handler http.Handler, opts ...ClientOption) (*Client, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient("test", "test@example.com", "test-token", append([]ClientOption{WithBaseURL(server.URL + "/api/v2")}, opts...)...)
+	return client, server
+}
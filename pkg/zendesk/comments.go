@@ -0,0 +1,63 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Comment represents a public reply or private note on a ticket
+type Comment struct {
+	ID          int64        `json:"id"`
+	Type        string       `json:"type"`
+	AuthorID    int64        `json:"author_id"`
+	Body        string       `json:"body"`
+	HTMLBody    string       `json:"html_body,omitempty"`
+	PlainBody   string       `json:"plain_body,omitempty"`
+	Public      bool         `json:"public"`
+	AuditID     int64        `json:"audit_id,omitempty"`
+	Via         *Via         `json:"via,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// Attachment represents a file attached to a comment
+type Attachment struct {
+	ID          int64  `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentURL  string `json:"content_url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// CommentsResponse represents the response from ticket comments API
+type CommentsResponse struct {
+	Comments []Comment `json:"comments"`
+	Users    []User    `json:"users,omitempty"`
+	Meta     *Meta     `json:"meta,omitempty"`
+	Links    *Links    `json:"links,omitempty"`
+}
+
+// ListTicketComments retrieves every comment of a ticket, oldest first, with
+// the comment authors side-loaded
+func (c *Client) ListTicketComments(ctx context.Context, ticketID int64) ([]Comment, *Sideloads, error) {
+	var comments []Comment
+	sideloads := &Sideloads{}
+	path := fmt.Sprintf("/tickets/%d/comments.json", ticketID)
+	params := map[string]string{"include": IncludeUsers}
+	err := c.walkPages(ctx, path, params, 0, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page CommentsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		comments = append(comments, page.Comments...)
+		sideloads.add(page.Users, nil, nil)
+		return &pageResult{Rows: len(page.Comments), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return comments, sideloads, nil
+}
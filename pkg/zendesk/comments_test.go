@@ -0,0 +1,54 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTicketComments(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets/42/comments.json", r.URL.Path)
+		assert.Equal(t, "users", r.URL.Query().Get("include"))
+		if r.URL.Query().Get("page[after]") == "" {
+			fmt.Fprintf(w, `{"comments":[{"id":1,"author_id":7,"body":"Help","public":true,"via":{"channel":"email"},"created_at":"2024-01-01T09:00:00Z"}],
+				"users":[{"id":7,"name":"Ana"}],
+				"meta":{"has_more":true},"links":{"next":"http://%s/api/v2/tickets/42/comments.json?include=users&page[after]=c1"}}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"comments":[{"id":2,"author_id":8,"body":"Note","public":false,"via":{"channel":"web"},"created_at":"2024-01-01T10:00:00Z",
+			"attachments":[{"id":5,"file_name":"log.txt","size":12}]}],
+			"users":[{"id":8,"name":"Ben"}],
+			"meta":{"has_more":false}}`)
+	}))
+
+	comments, sideloads, err := client.ListTicketComments(context.Background(), 42)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.True(t, comments[0].Public)
+	assert.Equal(t, "email", comments[0].Via.Channel)
+	assert.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), comments[0].CreatedAt)
+	assert.False(t, comments[1].Public)
+	assert.Equal(t, "log.txt", comments[1].Attachments[0].FileName)
+	assert.Equal(t, "Ana", sideloads.Users[7].Name)
+	assert.Equal(t, "Ben", sideloads.Users[8].Name)
+}
+
+func TestGetTicket(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets/42.json", r.URL.Path)
+		assert.Equal(t, "users,groups", r.URL.Query().Get("include"))
+		fmt.Fprint(w, `{"ticket":{"id":42,"status":"open","requester_id":7,"group_id":4},"users":[{"id":7,"name":"Ana"}],"groups":[{"id":4,"name":"Billing"}]}`)
+	}))
+
+	ticket, sideloads, err := client.GetTicket(context.Background(), 42, IncludeUsers, IncludeGroups)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), ticket.ID)
+	assert.Equal(t, "Ana", sideloads.Users[7].Name)
+	assert.Equal(t, "Billing", sideloads.Groups[4].Name)
+}
@@ -0,0 +1,142 @@
+package zendesk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is
+var (
+	ErrUnauthorized = errors.New("zendesk: unauthorized")
+	ErrForbidden    = errors.New("zendesk: forbidden")
+	ErrNotFound     = errors.New("zendesk: not found")
+	ErrRateLimited  = errors.New("zendesk: rate limited")
+	ErrServerError  = errors.New("zendesk: server error")
+)
+
+// maxErrorBody bounds how much of an error response is read
+const maxErrorBody = 64 << 10
+
+// APIError is an error response from the Zendesk API
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Code is the Zendesk error, e.g. RecordNotFound or InvalidEndpoint
+	Code string
+	// Description is the human readable explanation Zendesk gave
+	Description string
+	// Details holds per-field validation errors as returned by Zendesk
+	Details json.RawMessage
+	// RequestID identifies the request in Zendesk logs and support tickets
+	RequestID string
+	// RetryAfter is how long Zendesk asked to wait before retrying, if it did
+	RetryAfter time.Duration
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "API error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Code != "" {
+		fmt.Fprintf(&b, ": %s", e.Code)
+	}
+	if e.Description != "" {
+		fmt.Fprintf(&b, ": %s", e.Description)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+	return b.String()
+}
+
+// Is matches the sentinel error of the response status
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// apiErrorBody covers the error shapes returned by Zendesk APIs:
+// {"error":"RecordNotFound","description":"..."},
+// {"error":"invalid_client","error_description":"..."} (OAuth),
+// {"error":{"title":"...","message":"..."}} and
+// {"errors":[{"code":"...","title":"...","detail":"..."}]}
+type apiErrorBody struct {
+	Error            json.RawMessage `json:"error"`
+	Description      string          `json:"description"`
+	ErrorDescription string          `json:"error_description"`
+	Details          json.RawMessage `json:"details"`
+	Errors           []struct {
+		Code   string `json:"code"`
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+// newAPIError builds an APIError from an error response, reading its body
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Zendesk-Request-Id"),
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	if resp.Header.Get("Retry-After") != "" {
+		apiErr.RetryAfter = parseRetryAfter(resp.Header)
+	}
+
+	var body apiErrorBody
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&body); err != nil {
+		return apiErr
+	}
+	apiErr.Description = body.Description
+	if apiErr.Description == "" {
+		apiErr.Description = body.ErrorDescription
+	}
+	if len(body.Details) > 0 && string(body.Details) != "null" {
+		apiErr.Details = body.Details
+	}
+
+	var code string
+	var nested struct {
+		Title   string `json:"title"`
+		Message string `json:"message"`
+	}
+	switch {
+	case json.Unmarshal(body.Error, &code) == nil:
+		apiErr.Code = code
+	case json.Unmarshal(body.Error, &nested) == nil:
+		apiErr.Code = nested.Title
+		if apiErr.Description == "" {
+			apiErr.Description = nested.Message
+		}
+	}
+
+	// A missing, null or empty "error" falls back to the "errors" list
+	if apiErr.Code == "" && len(body.Errors) > 0 {
+		apiErr.Code = body.Errors[0].Code
+		if apiErr.Code == "" {
+			apiErr.Code = body.Errors[0].Title
+		}
+		if apiErr.Description == "" {
+			apiErr.Description = body.Errors[0].Detail
+		}
+	}
+	return apiErr
+}
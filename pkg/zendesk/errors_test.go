@@ -0,0 +1,86 @@
+package zendesk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_Decoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		sentinel    error
+		code        string
+		description string
+	}{
+		{"record not found", http.StatusNotFound, `{"error":"RecordNotFound","description":"Not found"}`, ErrNotFound, "RecordNotFound", "Not found"},
+		{"nested error", http.StatusForbidden, `{"error":{"title":"Forbidden","message":"You do not have access"}}`, ErrForbidden, "Forbidden", "You do not have access"},
+		{"errors list", http.StatusUnauthorized, `{"errors":[{"code":"InvalidToken","title":"Invalid token","detail":"Token expired"}]}`, ErrUnauthorized, "InvalidToken", "Token expired"},
+		{"null error with errors list", http.StatusUnprocessableEntity, `{"error":null,"errors":[{"code":"InvalidValue","detail":"Status is invalid"}]}`, nil, "InvalidValue", "Status is invalid"},
+		{"empty error with errors list", http.StatusUnauthorized, `{"error":"","errors":[{"title":"Invalid token","detail":"Token expired"}]}`, ErrUnauthorized, "Invalid token", "Token expired"},
+		{"no body", http.StatusServiceUnavailable, ``, ErrServerError, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Zendesk-Request-Id", "req-1")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			client.retryPolicy = RetryPolicy{MaxAttempts: 1}
+
+			err := client.TestConnection(context.Background())
+			require.Error(t, err)
+			if tt.sentinel != nil {
+				assert.True(t, errors.Is(err, tt.sentinel))
+			}
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.code, apiErr.Code)
+			assert.Equal(t, tt.description, apiErr.Description)
+			assert.Equal(t, "req-1", apiErr.RequestID)
+		})
+	}
+}
+
+func TestAPIError_ValidationDetails(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"error":"RecordInvalid","description":"Record validation errors","details":{"subject":[{"description":"Subject: cannot be blank"}]}}`)
+	}))
+
+	_, err := client.GetTickets(context.Background(), nil)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.JSONEq(t, `{"subject":[{"description":"Subject: cannot be blank"}]}`, string(apiErr.Details))
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, "API error: 422 Unprocessable Entity: RecordInvalid: Record validation errors", apiErr.Error())
+}
+
+func TestAPIError_RateLimited(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error":"APIRateLimitExceeded"}`)
+	}))
+
+	// The request deadline is shorter than Retry-After, so the 429 is surfaced
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := client.TestConnection(ctx)
+	require.True(t, errors.Is(err, ErrRateLimited))
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 60*time.Second, apiErr.RetryAfter)
+}
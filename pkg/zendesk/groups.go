@@ -0,0 +1,94 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Group represents a Zendesk agent group
+type Group struct {
+	ID          int64     `json:"id"`
+	URL         string    `json:"url"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Default     bool      `json:"default"`
+	Deleted     bool      `json:"deleted"`
+	IsPublic    bool      `json:"is_public"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// GroupsResponse represents the response from groups API
+type GroupsResponse struct {
+	Groups []Group `json:"groups"`
+	Meta   *Meta   `json:"meta,omitempty"`
+	Links  *Links  `json:"links,omitempty"`
+}
+
+// GroupMembership assigns an agent to a group
+type GroupMembership struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	UserID    int64     `json:"user_id"`
+	GroupID   int64     `json:"group_id"`
+	Default   bool      `json:"default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GroupMembershipsResponse represents the response from group memberships API
+type GroupMembershipsResponse struct {
+	GroupMemberships []GroupMembership `json:"group_memberships"`
+	Meta             *Meta             `json:"meta,omitempty"`
+	Links            *Links            `json:"links,omitempty"`
+}
+
+// IsDeleted reports whether the group has been deleted
+func (g Group) IsDeleted() bool {
+	return g.Deleted
+}
+
+// ListGroups retrieves every group across every page, up to maxRows groups (0 means no cap)
+func (c *Client) ListGroups(ctx context.Context, maxRows int) ([]Group, error) {
+	var groups []Group
+	err := c.walkPages(ctx, "/groups.json", nil, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page GroupsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Groups[:trimRows(len(page.Groups), remaining)]
+		groups = append(groups, rows...)
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ListGroupMemberships retrieves the memberships of a group, or of every
+// group when groupID is 0, up to maxRows memberships (0 means no cap)
+func (c *Client) ListGroupMemberships(ctx context.Context, groupID int64, maxRows int) ([]GroupMembership, error) {
+	path := "/group_memberships.json"
+	if groupID > 0 {
+		path = fmt.Sprintf("/groups/%d/memberships.json", groupID)
+	}
+
+	var memberships []GroupMembership
+	err := c.walkPages(ctx, path, nil, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page GroupMembershipsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.GroupMemberships[:trimRows(len(page.GroupMemberships), remaining)]
+		memberships = append(memberships, rows...)
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
@@ -0,0 +1,23 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListGroupMemberships_ByGroup(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/groups/5/memberships.json", r.URL.Path)
+		fmt.Fprint(w, `{"group_memberships":[{"id":1,"user_id":7,"group_id":5},{"id":2,"user_id":8,"group_id":5}],"meta":{"has_more":false}}`)
+	}))
+
+	memberships, err := client.ListGroupMemberships(context.Background(), 5, 1)
+	require.NoError(t, err)
+	require.Len(t, memberships, 1)
+	assert.Equal(t, int64(7), memberships[0].UserID)
+}
@@ -0,0 +1,239 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// IncrementalOptions controls an incremental export walk
+type IncrementalOptions struct {
+	// StartTime is used when no Cursor is given
+	StartTime time.Time
+	// Cursor resumes a cursor-based export from a previous checkpoint
+	Cursor string
+	// EndTime stops the walk once records were updated after it (zero means no end)
+	EndTime time.Time
+	// MaxRows caps the number of records returned (0 means no cap); the
+	// checkpoint of a capped walk points past the truncated page
+	MaxRows int
+	// IncludeDeleted keeps deleted tickets, users and organizations
+	IncludeDeleted bool
+}
+
+// IncrementalCheckpoint records where an incremental export stopped so it can be resumed
+type IncrementalCheckpoint struct {
+	Cursor      string `json:"cursor,omitempty"`
+	StartTime   int64  `json:"start_time,omitempty"`
+	EndOfStream bool   `json:"end_of_stream"`
+}
+
+// IncrementalTicketsResponse represents a page of the incremental ticket export
+type IncrementalTicketsResponse struct {
+	Tickets     []Ticket `json:"tickets"`
+	AfterCursor *string  `json:"after_cursor,omitempty"`
+	AfterURL    *string  `json:"after_url,omitempty"`
+	EndOfStream bool     `json:"end_of_stream"`
+}
+
+// IncrementalUsersResponse represents a page of the incremental user export
+type IncrementalUsersResponse struct {
+	Users       []User  `json:"users"`
+	AfterCursor *string `json:"after_cursor,omitempty"`
+	AfterURL    *string `json:"after_url,omitempty"`
+	EndOfStream bool    `json:"end_of_stream"`
+}
+
+// IncrementalOrganizationsResponse represents a page of the time-based incremental organization export
+type IncrementalOrganizationsResponse struct {
+	Organizations []Organization `json:"organizations"`
+	NextPage      *string        `json:"next_page,omitempty"`
+	EndTime       int64          `json:"end_time"`
+	EndOfStream   bool           `json:"end_of_stream"`
+}
+
+// incrementalPage describes a single decoded incremental export page
+type incrementalPage struct {
+	Rows        int
+	NextURL     string
+	Cursor      string
+	EndTime     int64
+	EndOfStream bool
+	// PastEnd is set once the page contained records updated after IncrementalOptions.EndTime
+	PastEnd bool
+}
+
+// incrementalDecoder decodes one export page, keeping at most remaining rows
+// (remaining <= 0 means no limit)
+type incrementalDecoder func(body io.Reader, remaining int) (*incrementalPage, error)
+
+// walkIncremental requests endpoint and follows the export's next links until
+// end of stream, EndTime or MaxRows is reached
+func (c *Client) walkIncremental(ctx context.Context, endpoint string, opts IncrementalOptions, decode incrementalDecoder) (*IncrementalCheckpoint, error) {
+	checkpoint := &IncrementalCheckpoint{Cursor: opts.Cursor, StartTime: opts.StartTime.Unix()}
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		remaining := 0
+		if opts.MaxRows > 0 {
+			remaining = opts.MaxRows - total
+		}
+
+		resp, err := c.request(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		page, err := decode(resp.Body, remaining)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		total += page.Rows
+		if page.Cursor != "" {
+			checkpoint.Cursor = page.Cursor
+		}
+		if page.EndTime > 0 {
+			checkpoint.StartTime = page.EndTime
+		}
+		checkpoint.EndOfStream = page.EndOfStream
+
+		if page.EndOfStream || page.PastEnd || page.NextURL == "" {
+			return checkpoint, nil
+		}
+		if opts.MaxRows > 0 && total >= opts.MaxRows {
+			return checkpoint, nil
+		}
+
+		endpoint, err = c.relativeEndpoint(page.NextURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// incrementalEndpoint builds the first request of an export, preferring a cursor over start_time
+func incrementalEndpoint(path string, opts IncrementalOptions) string {
+	params := map[string]string{}
+	if opts.Cursor != "" {
+		params["cursor"] = opts.Cursor
+	} else {
+		params["start_time"] = strconv.FormatInt(opts.StartTime.Unix(), 10)
+	}
+	return buildEndpoint(path, params)
+}
+
+// timeAfter reports whether t is after end (zero end never matches)
+func timeAfter(t, end time.Time) bool {
+	return !end.IsZero() && t.After(end)
+}
+
+// IncrementalTickets walks the cursor-based incremental ticket export,
+// calling fn once per page, and returns the checkpoint to resume from
+func (c *Client) IncrementalTickets(ctx context.Context, opts IncrementalOptions, fn func(tickets []Ticket) error) (*IncrementalCheckpoint, error) {
+	endpoint := incrementalEndpoint("/incremental/tickets/cursor.json", opts)
+	return c.walkIncremental(ctx, endpoint, opts, func(body io.Reader, remaining int) (*incrementalPage, error) {
+		var page IncrementalTicketsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		result := &incrementalPage{EndOfStream: page.EndOfStream}
+		if page.AfterCursor != nil {
+			result.Cursor = *page.AfterCursor
+		}
+		if page.AfterURL != nil {
+			result.NextURL = *page.AfterURL
+		}
+
+		rows := make([]Ticket, 0, len(page.Tickets))
+		for _, ticket := range page.Tickets {
+			if timeAfter(ticket.UpdatedAt, opts.EndTime) {
+				result.PastEnd = true
+				continue
+			}
+			if ticket.IsDeleted() && !opts.IncludeDeleted {
+				continue
+			}
+			rows = append(rows, ticket)
+		}
+		rows = rows[:trimRows(len(rows), remaining)]
+		result.Rows = len(rows)
+		return result, fn(rows)
+	})
+}
+
+// IncrementalUsers walks the cursor-based incremental user export,
+// calling fn once per page, and returns the checkpoint to resume from
+func (c *Client) IncrementalUsers(ctx context.Context, opts IncrementalOptions, fn func(users []User) error) (*IncrementalCheckpoint, error) {
+	endpoint := incrementalEndpoint("/incremental/users/cursor.json", opts)
+	return c.walkIncremental(ctx, endpoint, opts, func(body io.Reader, remaining int) (*incrementalPage, error) {
+		var page IncrementalUsersResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		result := &incrementalPage{EndOfStream: page.EndOfStream}
+		if page.AfterCursor != nil {
+			result.Cursor = *page.AfterCursor
+		}
+		if page.AfterURL != nil {
+			result.NextURL = *page.AfterURL
+		}
+
+		rows := make([]User, 0, len(page.Users))
+		for _, user := range page.Users {
+			if timeAfter(user.UpdatedAt, opts.EndTime) {
+				result.PastEnd = true
+				continue
+			}
+			if user.IsDeleted() && !opts.IncludeDeleted {
+				continue
+			}
+			rows = append(rows, user)
+		}
+		rows = rows[:trimRows(len(rows), remaining)]
+		result.Rows = len(rows)
+		return result, fn(rows)
+	})
+}
+
+// IncrementalOrganizations walks the time-based incremental organization export,
+// calling fn once per page, and returns the checkpoint to resume from
+func (c *Client) IncrementalOrganizations(ctx context.Context, opts IncrementalOptions, fn func(orgs []Organization) error) (*IncrementalCheckpoint, error) {
+	// The organization export is time-based only, so a cursor cannot be used here
+	opts.Cursor = ""
+	endpoint := incrementalEndpoint("/incremental/organizations.json", opts)
+	return c.walkIncremental(ctx, endpoint, opts, func(body io.Reader, remaining int) (*incrementalPage, error) {
+		var page IncrementalOrganizationsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		result := &incrementalPage{EndOfStream: page.EndOfStream, EndTime: page.EndTime}
+		if page.NextPage != nil {
+			result.NextURL = *page.NextPage
+		}
+
+		rows := make([]Organization, 0, len(page.Organizations))
+		for _, org := range page.Organizations {
+			if timeAfter(org.UpdatedAt, opts.EndTime) {
+				result.PastEnd = true
+				continue
+			}
+			if org.IsDeleted() && !opts.IncludeDeleted {
+				continue
+			}
+			rows = append(rows, org)
+		}
+		rows = rows[:trimRows(len(rows), remaining)]
+		result.Rows = len(rows)
+		return result, fn(rows)
+	})
+}
@@ -0,0 +1,54 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncrementalTickets_EndOfStream(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/incremental/tickets/cursor.json", r.URL.Path)
+		if r.URL.Query().Get("cursor") == "" {
+			assert.Equal(t, "1700000000", r.URL.Query().Get("start_time"))
+			fmt.Fprintf(w, `{"tickets":[{"id":1,"status":"open"},{"id":2,"status":"deleted"}],"after_cursor":"c1","after_url":"http://%s/api/v2/incremental/tickets/cursor.json?cursor=c1","end_of_stream":false}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"tickets":[{"id":3,"status":"solved"}],"after_cursor":"c2","end_of_stream":true}`)
+	}))
+
+	var tickets []Ticket
+	checkpoint, err := client.IncrementalTickets(context.Background(), IncrementalOptions{StartTime: time.Unix(1700000000, 0)}, func(page []Ticket) error {
+		tickets = append(tickets, page...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	assert.Equal(t, int64(3), tickets[1].ID)
+	assert.Equal(t, "c2", checkpoint.Cursor)
+	assert.True(t, checkpoint.EndOfStream)
+}
+
+func TestIncrementalOrganizations_StopsAtEndTime(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"organizations":[{"id":1,"updated_at":"2024-01-01T00:00:00Z"},{"id":2,"updated_at":"2024-03-01T00:00:00Z"}],"next_page":"http://%s/api/v2/incremental/organizations.json?start_time=1709251200","end_time":1709251200,"end_of_stream":false}`, r.Host)
+	}))
+
+	var orgs []Organization
+	checkpoint, err := client.IncrementalOrganizations(context.Background(), IncrementalOptions{
+		StartTime:      time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:        time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		IncludeDeleted: true,
+	}, func(page []Organization) error {
+		orgs = append(orgs, page...)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+	assert.Equal(t, int64(1709251200), checkpoint.StartTime)
+}
@@ -0,0 +1,124 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MetricDuration is a duration in minutes measured in calendar and business hours
+type MetricDuration struct {
+	Calendar *int64 `json:"calendar,omitempty"`
+	Business *int64 `json:"business,omitempty"`
+}
+
+// TicketMetric represents the reply, resolution and wait time metrics of a ticket
+type TicketMetric struct {
+	ID                           int64          `json:"id"`
+	URL                          string         `json:"url"`
+	TicketID                     int64          `json:"ticket_id"`
+	GroupStations                int64          `json:"group_stations"`
+	AssigneeStations             int64          `json:"assignee_stations"`
+	Reopens                      int64          `json:"reopens"`
+	Replies                      int64          `json:"replies"`
+	AssigneeUpdatedAt            *time.Time     `json:"assignee_updated_at,omitempty"`
+	RequesterUpdatedAt           *time.Time     `json:"requester_updated_at,omitempty"`
+	StatusUpdatedAt              *time.Time     `json:"status_updated_at,omitempty"`
+	InitiallyAssignedAt          *time.Time     `json:"initially_assigned_at,omitempty"`
+	AssignedAt                   *time.Time     `json:"assigned_at,omitempty"`
+	SolvedAt                     *time.Time     `json:"solved_at,omitempty"`
+	LatestCommentAddedAt         *time.Time     `json:"latest_comment_added_at,omitempty"`
+	ReplyTimeInMinutes           MetricDuration `json:"reply_time_in_minutes"`
+	FirstResolutionTimeInMinutes MetricDuration `json:"first_resolution_time_in_minutes"`
+	FullResolutionTimeInMinutes  MetricDuration `json:"full_resolution_time_in_minutes"`
+	AgentWaitTimeInMinutes       MetricDuration `json:"agent_wait_time_in_minutes"`
+	RequesterWaitTimeInMinutes   MetricDuration `json:"requester_wait_time_in_minutes"`
+	OnHoldTimeInMinutes          MetricDuration `json:"on_hold_time_in_minutes"`
+	CreatedAt                    time.Time      `json:"created_at"`
+	UpdatedAt                    time.Time      `json:"updated_at"`
+}
+
+// TicketMetricsResponse represents the response from ticket metrics API
+type TicketMetricsResponse struct {
+	TicketMetrics []TicketMetric `json:"ticket_metrics"`
+	Meta          *Meta          `json:"meta,omitempty"`
+	Links         *Links         `json:"links,omitempty"`
+}
+
+// TicketMetricResponse represents the response for a single ticket's metrics
+type TicketMetricResponse struct {
+	TicketMetric TicketMetric `json:"ticket_metric"`
+}
+
+// WalkTicketMetrics calls fn for every page of ticket metrics, following
+// cursor pagination until exhaustion or maxRows metrics (0 means no cap)
+func (c *Client) WalkTicketMetrics(ctx context.Context, maxRows int, fn func(metrics []TicketMetric) error) error {
+	return c.walkPages(ctx, "/ticket_metrics.json", nil, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page TicketMetricsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.TicketMetrics[:trimRows(len(page.TicketMetrics), remaining)]
+		if err := fn(rows); err != nil {
+			return nil, err
+		}
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+}
+
+// ListTicketMetrics retrieves the metrics of every ticket across every page
+func (c *Client) ListTicketMetrics(ctx context.Context, maxRows int) ([]TicketMetric, error) {
+	var metrics []TicketMetric
+	err := c.WalkTicketMetrics(ctx, maxRows, func(page []TicketMetric) error {
+		metrics = append(metrics, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// GetTicketMetric retrieves the metrics of a single ticket
+func (c *Client) GetTicketMetric(ctx context.Context, ticketID int64) (*TicketMetric, error) {
+	resp, err := c.request(ctx, "GET", fmt.Sprintf("/tickets/%d/metrics.json", ticketID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TicketMetricResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result.TicketMetric, nil
+}
+
+// ListTicketsWithMetrics retrieves tickets with their metrics side-loaded
+// through include=metric_sets, saving one request per ticket
+func (c *Client) ListTicketsWithMetrics(ctx context.Context, params map[string]string, maxRows int) ([]Ticket, []TicketMetric, error) {
+	query := map[string]string{"include": "metric_sets"}
+	for k, v := range params {
+		query[k] = v
+	}
+
+	var tickets []Ticket
+	var metrics []TicketMetric
+	err := c.walkPages(ctx, "/tickets.json", query, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page TicketsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Tickets[:trimRows(len(page.Tickets), remaining)]
+		tickets = append(tickets, rows...)
+		metrics = append(metrics, page.MetricSets...)
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return tickets, metrics, nil
+}
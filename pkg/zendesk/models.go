@@ -1,50 +1,163 @@
 package zendesk
 
+import "time"
+
 // Ticket represents a Zendesk ticket
 type Ticket struct {
-	ID          int64   `json:"id"`
-	URL         string  `json:"url"`
-	ExternalID  *string `json:"external_id,omitempty"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
-	Type        *string `json:"type,omitempty"`
-	Subject     *string `json:"subject,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Priority    *string `json:"priority,omitempty"`
-	Status      string  `json:"status"`
-	RequesterID int64   `json:"requester_id"`
-	AssigneeID  *int64  `json:"assignee_id,omitempty"`
-	Tags        []string `json:"tags,omitempty"`
+	ID                 int64               `json:"id"`
+	URL                string              `json:"url"`
+	ExternalID         *string             `json:"external_id,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	Type               *string             `json:"type,omitempty"`
+	Subject            *string             `json:"subject,omitempty"`
+	RawSubject         *string             `json:"raw_subject,omitempty"`
+	Description        *string             `json:"description,omitempty"`
+	Priority           *string             `json:"priority,omitempty"`
+	Status             string              `json:"status"`
+	Recipient          *string             `json:"recipient,omitempty"`
+	RequesterID        int64               `json:"requester_id"`
+	SubmitterID        int64               `json:"submitter_id"`
+	AssigneeID         *int64              `json:"assignee_id,omitempty"`
+	OrganizationID     *int64              `json:"organization_id,omitempty"`
+	GroupID            *int64              `json:"group_id,omitempty"`
+	CollaboratorIDs    []int64             `json:"collaborator_ids,omitempty"`
+	FollowerIDs        []int64             `json:"follower_ids,omitempty"`
+	EmailCCIDs         []int64             `json:"email_cc_ids,omitempty"`
+	ProblemID          *int64              `json:"problem_id,omitempty"`
+	HasIncidents       bool                `json:"has_incidents"`
+	IsPublic           bool                `json:"is_public"`
+	DueAt              *time.Time          `json:"due_at,omitempty"`
+	Via                *Via                `json:"via,omitempty"`
+	Tags               []string            `json:"tags,omitempty"`
+	CustomFields       []CustomFieldValue  `json:"custom_fields,omitempty"`
+	SatisfactionRating *TicketSatisfaction `json:"satisfaction_rating,omitempty"`
+	FollowupIDs        []int64             `json:"followup_ids,omitempty"`
+	TicketFormID       *int64              `json:"ticket_form_id,omitempty"`
+	BrandID            *int64              `json:"brand_id,omitempty"`
+	AllowChannelback   bool                `json:"allow_channelback"`
+	AllowAttachments   bool                `json:"allow_attachments"`
+}
+
+// Via describes the channel a ticket or change came in through
+type Via struct {
+	Channel string `json:"channel"`
+}
+
+// TicketSatisfaction is the satisfaction rating summary embedded in a ticket
+type TicketSatisfaction struct {
+	ID      *int64  `json:"id,omitempty"`
+	Score   string  `json:"score"`
+	Comment *string `json:"comment,omitempty"`
+	Reason  *string `json:"reason,omitempty"`
 }
 
 // User represents a Zendesk user
 type User struct {
-	ID        int64   `json:"id"`
-	URL       string  `json:"url"`
-	Name      string  `json:"name"`
-	Email     string  `json:"email"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
-	Role      string  `json:"role"`
-	Active    bool    `json:"active"`
+	ID                   int64                  `json:"id"`
+	URL                  string                 `json:"url"`
+	Name                 string                 `json:"name"`
+	Email                string                 `json:"email"`
+	CreatedAt            time.Time              `json:"created_at"`
+	UpdatedAt            time.Time              `json:"updated_at"`
+	TimeZone             string                 `json:"time_zone,omitempty"`
+	IANATimeZone         string                 `json:"iana_time_zone,omitempty"`
+	Phone                *string                `json:"phone,omitempty"`
+	Locale               string                 `json:"locale,omitempty"`
+	LocaleID             int64                  `json:"locale_id,omitempty"`
+	OrganizationID       *int64                 `json:"organization_id,omitempty"`
+	Role                 string                 `json:"role"`
+	RoleType             *int64                 `json:"role_type,omitempty"`
+	CustomRoleID         *int64                 `json:"custom_role_id,omitempty"`
+	Verified             bool                   `json:"verified"`
+	ExternalID           *string                `json:"external_id,omitempty"`
+	Tags                 []string               `json:"tags,omitempty"`
+	Alias                *string                `json:"alias,omitempty"`
+	Active               bool                   `json:"active"`
+	Shared               bool                   `json:"shared"`
+	SharedAgent          bool                   `json:"shared_agent"`
+	LastLoginAt          *time.Time             `json:"last_login_at,omitempty"`
+	TwoFactorAuthEnabled *bool                  `json:"two_factor_auth_enabled,omitempty"`
+	Signature            *string                `json:"signature,omitempty"`
+	Details              *string                `json:"details,omitempty"`
+	Notes                *string                `json:"notes,omitempty"`
+	Moderator            bool                   `json:"moderator"`
+	TicketRestriction    *string                `json:"ticket_restriction,omitempty"`
+	OnlyPrivateComments  bool                   `json:"only_private_comments"`
+	RestrictedAgent      bool                   `json:"restricted_agent"`
+	Suspended            bool                   `json:"suspended"`
+	ChatOnly             bool                   `json:"chat_only"`
+	DefaultGroupID       *int64                 `json:"default_group_id,omitempty"`
+	UserFields           map[string]interface{} `json:"user_fields,omitempty"`
 }
 
 // Organization represents a Zendesk organization
 type Organization struct {
-	ID          int64    `json:"id"`
-	URL         string   `json:"url"`
-	Name        string   `json:"name"`
-	CreatedAt   string   `json:"created_at"`
-	UpdatedAt   string   `json:"updated_at"`
-	DomainNames []string `json:"domain_names,omitempty"`
+	ID                 int64                  `json:"id"`
+	URL                string                 `json:"url"`
+	ExternalID         *string                `json:"external_id,omitempty"`
+	Name               string                 `json:"name"`
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+	DeletedAt          *time.Time             `json:"deleted_at,omitempty"`
+	DomainNames        []string               `json:"domain_names,omitempty"`
+	Details            *string                `json:"details,omitempty"`
+	Notes              *string                `json:"notes,omitempty"`
+	GroupID            *int64                 `json:"group_id,omitempty"`
+	SharedTickets      bool                   `json:"shared_tickets"`
+	SharedComments     bool                   `json:"shared_comments"`
+	Tags               []string               `json:"tags,omitempty"`
+	OrganizationFields map[string]interface{} `json:"organization_fields,omitempty"`
+}
+
+// IsDeleted reports whether the ticket was deleted (incremental export only)
+func (t Ticket) IsDeleted() bool {
+	return t.Status == "deleted"
+}
+
+// IsDeleted reports whether the user was deleted (incremental export only)
+func (u User) IsDeleted() bool {
+	return !u.Active
+}
+
+// IsDeleted reports whether the organization was deleted (incremental export only)
+func (o Organization) IsDeleted() bool {
+	return o.DeletedAt != nil
+}
+
+// Meta represents cursor pagination metadata
+type Meta struct {
+	HasMore      bool   `json:"has_more"`
+	AfterCursor  string `json:"after_cursor,omitempty"`
+	BeforeCursor string `json:"before_cursor,omitempty"`
+}
+
+// Links represents cursor pagination links
+type Links struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 }
 
 // TicketsResponse represents the response from tickets API
 type TicketsResponse struct {
-	Tickets      []Ticket `json:"tickets"`
-	Count        *int     `json:"count,omitempty"`
-	NextPage     *string  `json:"next_page,omitempty"`
-	PreviousPage *string  `json:"previous_page,omitempty"`
+	Tickets       []Ticket       `json:"tickets"`
+	MetricSets    []TicketMetric `json:"metric_sets,omitempty"`
+	Users         []User         `json:"users,omitempty"`
+	Groups        []Group        `json:"groups,omitempty"`
+	Organizations []Organization `json:"organizations,omitempty"`
+	Count         *int           `json:"count,omitempty"`
+	NextPage      *string        `json:"next_page,omitempty"`
+	PreviousPage  *string        `json:"previous_page,omitempty"`
+	Meta          *Meta          `json:"meta,omitempty"`
+	Links         *Links         `json:"links,omitempty"`
+}
+
+// TicketResponse represents the response for a single ticket with its side-loaded records
+type TicketResponse struct {
+	Ticket        Ticket         `json:"ticket"`
+	Users         []User         `json:"users,omitempty"`
+	Groups        []Group        `json:"groups,omitempty"`
+	Organizations []Organization `json:"organizations,omitempty"`
 }
 
 // UsersResponse represents the response from users API
@@ -53,6 +166,8 @@ type UsersResponse struct {
 	Count        *int     `json:"count,omitempty"`
 	NextPage     *string  `json:"next_page,omitempty"`
 	PreviousPage *string  `json:"previous_page,omitempty"`
+	Meta         *Meta    `json:"meta,omitempty"`
+	Links        *Links   `json:"links,omitempty"`
 }
 
 // OrganizationsResponse represents the response from organizations API
@@ -61,6 +176,8 @@ type OrganizationsResponse struct {
 	Count         *int           `json:"count,omitempty"`
 	NextPage      *string        `json:"next_page,omitempty"`
 	PreviousPage  *string        `json:"previous_page,omitempty"`
+	Meta          *Meta          `json:"meta,omitempty"`
+	Links         *Links         `json:"links,omitempty"`
 }
 
 // ErrorResponse represents an error response from Zendesk API
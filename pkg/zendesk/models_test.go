@@ -0,0 +1,79 @@
+package zendesk
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicket_UnmarshalJSON(t *testing.T) {
+	var ticket Ticket
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 35436,
+		"created_at": "2024-03-01T09:30:00Z",
+		"updated_at": "2024-03-02T10:00:00Z",
+		"status": "open",
+		"requester_id": 20978392,
+		"submitter_id": 76872,
+		"due_at": null,
+		"via": {"channel": "email", "source": {"from": {}, "to": {}}},
+		"tags": ["enterprise", "other_tag"],
+		"satisfaction_rating": {"id": 1234, "score": "good", "comment": "Great support!"},
+		"brand_id": 7,
+		"ticket_form_id": 12,
+		"has_incidents": true
+	}`), &ticket))
+
+	assert.Equal(t, time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), ticket.CreatedAt)
+	assert.Equal(t, time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), ticket.UpdatedAt)
+	assert.Nil(t, ticket.DueAt)
+	require.NotNil(t, ticket.Via)
+	assert.Equal(t, "email", ticket.Via.Channel)
+	require.NotNil(t, ticket.SatisfactionRating)
+	assert.Equal(t, "good", ticket.SatisfactionRating.Score)
+	assert.Equal(t, int64(7), *ticket.BrandID)
+	assert.Equal(t, int64(12), *ticket.TicketFormID)
+	assert.True(t, ticket.HasIncidents)
+}
+
+func TestUser_UnmarshalJSON(t *testing.T) {
+	var user User
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 7,
+		"name": "Ana",
+		"created_at": "2023-06-01T00:00:00Z",
+		"last_login_at": null,
+		"organization_id": 3,
+		"time_zone": "Copenhagen",
+		"iana_time_zone": "Europe/Copenhagen",
+		"user_fields": {"plan": "gold", "seats": 12}
+	}`), &user))
+
+	assert.Equal(t, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), user.CreatedAt)
+	assert.Nil(t, user.LastLoginAt)
+	assert.Equal(t, int64(3), *user.OrganizationID)
+	assert.Equal(t, "Europe/Copenhagen", user.IANATimeZone)
+	assert.Equal(t, "gold", user.UserFields["plan"])
+	assert.Equal(t, float64(12), user.UserFields["seats"])
+}
+
+func TestOrganization_UnmarshalJSON(t *testing.T) {
+	var org Organization
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": 3,
+		"name": "Acme",
+		"created_at": "2022-01-01T00:00:00Z",
+		"updated_at": "2022-02-01T00:00:00Z",
+		"deleted_at": null,
+		"tags": ["vip"],
+		"organization_fields": {"region": "emea"}
+	}`), &org))
+
+	assert.Equal(t, time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC), org.UpdatedAt)
+	assert.Nil(t, org.DeletedAt)
+	assert.Equal(t, []string{"vip"}, org.Tags)
+	assert.Equal(t, "emea", org.OrganizationFields["region"])
+}
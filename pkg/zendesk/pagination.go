@@ -0,0 +1,192 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaxPageSize is the largest page size Zendesk accepts for cursor pagination
+const MaxPageSize = 100
+
+// pageResult describes a single decoded page
+type pageResult struct {
+	Rows  int
+	Meta  *Meta
+	Links *Links
+}
+
+// pageDecoder decodes one page from body, keeping at most remaining rows
+// (remaining <= 0 means no limit)
+type pageDecoder func(body io.Reader, remaining int) (*pageResult, error)
+
+// buildEndpoint appends params to path as an encoded query string
+func buildEndpoint(path string, params map[string]string) string {
+	if len(params) == 0 {
+		return path
+	}
+	values := url.Values{}
+	for k, v := range params {
+		values.Set(k, v)
+	}
+	return path + "?" + values.Encode()
+}
+
+// walkPages requests path with cursor pagination and follows links.next
+// until meta.has_more is false or maxRows rows have been read (0 means no cap)
+func (c *Client) walkPages(ctx context.Context, path string, params map[string]string, maxRows int, decode pageDecoder) error {
+	pageSize := MaxPageSize
+	if maxRows > 0 && maxRows < pageSize {
+		pageSize = maxRows
+	}
+
+	query := make(map[string]string, len(params)+1)
+	for k, v := range params {
+		query[k] = v
+	}
+	query["page[size]"] = strconv.Itoa(pageSize)
+	endpoint := buildEndpoint(path, query)
+
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		remaining := 0
+		if maxRows > 0 {
+			remaining = maxRows - total
+		}
+
+		resp, err := c.request(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return err
+		}
+		page, err := decode(resp.Body, remaining)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		total += page.Rows
+		if maxRows > 0 && total >= maxRows {
+			return nil
+		}
+		if page.Meta == nil || !page.Meta.HasMore || page.Links == nil || page.Links.Next == "" {
+			return nil
+		}
+
+		endpoint, err = c.relativeEndpoint(page.Links.Next)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// relativeEndpoint converts an absolute next-page link into an endpoint
+// relative to the client base URL
+func (c *Client) relativeEndpoint(link string) (string, error) {
+	if !strings.HasPrefix(link, c.baseURL) {
+		return "", fmt.Errorf("unexpected pagination link: %s", link)
+	}
+	return strings.TrimPrefix(link, c.baseURL), nil
+}
+
+// trimRows returns the number of rows to keep from a page of n rows
+func trimRows(n, remaining int) int {
+	if remaining > 0 && n > remaining {
+		return remaining
+	}
+	return n
+}
+
+// WalkTickets calls fn for every page of tickets, following cursor
+// pagination until exhaustion or maxRows tickets (0 means no cap)
+func (c *Client) WalkTickets(ctx context.Context, params map[string]string, maxRows int, fn func(tickets []Ticket) error) error {
+	return c.walkPages(ctx, "/tickets.json", params, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page TicketsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Tickets[:trimRows(len(page.Tickets), remaining)]
+		if err := fn(rows); err != nil {
+			return nil, err
+		}
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+}
+
+// WalkUsers calls fn for every page of users, following cursor
+// pagination until exhaustion or maxRows users (0 means no cap)
+func (c *Client) WalkUsers(ctx context.Context, params map[string]string, maxRows int, fn func(users []User) error) error {
+	return c.walkPages(ctx, "/users.json", params, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page UsersResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Users[:trimRows(len(page.Users), remaining)]
+		if err := fn(rows); err != nil {
+			return nil, err
+		}
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+}
+
+// WalkOrganizations calls fn for every page of organizations, following
+// cursor pagination until exhaustion or maxRows organizations (0 means no cap)
+func (c *Client) WalkOrganizations(ctx context.Context, params map[string]string, maxRows int, fn func(orgs []Organization) error) error {
+	return c.walkPages(ctx, "/organizations.json", params, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page OrganizationsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Organizations[:trimRows(len(page.Organizations), remaining)]
+		if err := fn(rows); err != nil {
+			return nil, err
+		}
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+}
+
+// ListTickets retrieves all tickets matching params across every page
+func (c *Client) ListTickets(ctx context.Context, params map[string]string, maxRows int) ([]Ticket, error) {
+	var tickets []Ticket
+	err := c.WalkTickets(ctx, params, maxRows, func(page []Ticket) error {
+		tickets = append(tickets, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// ListUsers retrieves all users matching params across every page
+func (c *Client) ListUsers(ctx context.Context, params map[string]string, maxRows int) ([]User, error) {
+	var users []User
+	err := c.WalkUsers(ctx, params, maxRows, func(page []User) error {
+		users = append(users, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListOrganizations retrieves all organizations matching params across every page
+func (c *Client) ListOrganizations(ctx context.Context, params map[string]string, maxRows int) ([]Organization, error) {
+	var orgs []Organization
+	err := c.WalkOrganizations(ctx, params, maxRows, func(page []Organization) error {
+		orgs = append(orgs, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
@@ -0,0 +1,70 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ticketPages serves three pages of two tickets each
+func ticketPages(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets.json", r.URL.Path)
+		page := 0
+		if after := r.URL.Query().Get("page[after]"); after != "" {
+			fmt.Sscanf(after, "%d", &page)
+		}
+		hasMore := page < 2
+		next := ""
+		if hasMore {
+			next = fmt.Sprintf("http://%s/api/v2/tickets.json?page[size]=2&page[after]=%d", r.Host, page+1)
+		}
+		fmt.Fprintf(w, `{"tickets":[{"id":%d,"status":"open"},{"id":%d,"status":"open"}],"meta":{"has_more":%t},"links":{"next":%q}}`,
+			page*2+1, page*2+2, hasMore, next)
+	})
+}
+
+func TestListTickets_FollowsCursor(t *testing.T) {
+	client, _ := newTestClient(t, ticketPages(t))
+
+	tickets, err := client.ListTickets(context.Background(), nil, 0)
+	require.NoError(t, err)
+	require.Len(t, tickets, 6)
+	assert.Equal(t, int64(6), tickets[5].ID)
+}
+
+func TestListTickets_MaxRows(t *testing.T) {
+	client, _ := newTestClient(t, ticketPages(t))
+
+	tickets, err := client.ListTickets(context.Background(), nil, 3)
+	require.NoError(t, err)
+	require.Len(t, tickets, 3)
+	assert.Equal(t, int64(3), tickets[2].ID)
+}
+
+func TestListTickets_RejectsForeignLink(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tickets":[{"id":1}],"meta":{"has_more":true},"links":{"next":"https://evil.example.com/api/v2/tickets.json"}}`)
+	}))
+
+	_, err := client.ListTickets(context.Background(), nil, 0)
+	assert.Error(t, err)
+}
+
+func TestListTickets_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		cancel()
+		fmt.Fprintf(w, `{"tickets":[{"id":1}],"meta":{"has_more":true},"links":{"next":"http://%s/api/v2/tickets.json?page[after]=x"}}`, r.Host)
+	}))
+
+	_, err := client.ListTickets(ctx, nil, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,233 @@
+package zendesk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRateLimit is the requests-per-minute budget assumed until Zendesk
+	// reports the account limit through X-Rate-Limit
+	defaultRateLimit = 200
+	// defaultRetryAfter is used when a 429 response carries no Retry-After header
+	defaultRetryAfter = 10 * time.Second
+	// maxRateLimitRetries bounds how many 429 responses a single request waits out
+	maxRateLimitRetries = 3
+)
+
+// RateLimitStats is a snapshot of a rate limiter's state
+type RateLimitStats struct {
+	Limit        int       `json:"limit"`
+	Remaining    int       `json:"remaining"`
+	Tokens       float64   `json:"tokens"`
+	Throttled    int64     `json:"throttled"`
+	BlockedUntil time.Time `json:"blocked_until,omitempty"`
+}
+
+// RateLimiter is a token bucket refilled at the Zendesk per-minute limit
+type RateLimiter struct {
+	mu           sync.Mutex
+	limit        int
+	remaining    int
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	throttled    int64
+}
+
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = map[string]*RateLimiter{}
+)
+
+// NewRateLimiter creates a rate limiter with a full bucket of limit tokens per minute
+func NewRateLimiter(limit int) *RateLimiter {
+	return &RateLimiter{
+		limit:     limit,
+		remaining: -1,
+		tokens:    float64(limit),
+		last:      time.Now(),
+	}
+}
+
+// rateLimiterFor returns the limiter shared by every client of an account,
+// identified by its API base URL
+func rateLimiterFor(baseURL string) *RateLimiter {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	limiter, ok := rateLimiters[baseURL]
+	if !ok {
+		limiter = NewRateLimiter(defaultRateLimit)
+		rateLimiters[baseURL] = limiter
+	}
+	return limiter
+}
+
+// refill adds the tokens earned since the last call; mu must be held
+func (l *RateLimiter) refill(now time.Time) {
+	rate := float64(l.limit) / 60
+	l.tokens += now.Sub(l.last).Seconds() * rate
+	if l.tokens > float64(l.limit) {
+		l.tokens = float64(l.limit)
+	}
+	l.last = now
+}
+
+// reserve takes a token, or returns how long to wait before trying again
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+	l.refill(now)
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / (float64(l.limit) / 60) * float64(time.Second))
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Update syncs the bucket with the rate limit headers of a response
+func (l *RateLimiter) Update(header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit, err := strconv.Atoi(header.Get("X-Rate-Limit")); err == nil && limit > 0 {
+		l.limit = limit
+	}
+	if remaining, err := strconv.Atoi(header.Get("X-Rate-Limit-Remaining")); err == nil && remaining >= 0 {
+		l.remaining = remaining
+		l.refill(time.Now())
+		if float64(remaining) < l.tokens {
+			l.tokens = float64(remaining)
+		}
+	}
+}
+
+// Block stops all requests until wait has elapsed
+func (l *RateLimiter) Block(wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.throttled++
+	l.tokens = 0
+	if until := time.Now().Add(wait); until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+// Stats returns a snapshot of the limiter state
+func (l *RateLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	stats := RateLimitStats{
+		Limit:     l.limit,
+		Remaining: l.remaining,
+		Tokens:    l.tokens,
+		Throttled: l.throttled,
+	}
+	if time.Now().Before(l.blockedUntil) {
+		stats.BlockedUntil = l.blockedUntil
+	}
+	return stats
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return defaultRetryAfter
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return defaultRetryAfter
+}
+
+// rateLimitTransport throttles requests through a RateLimiter and waits out
+// 429 responses while the request context allows it
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.limiter.Update(resp.Header)
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+
+		wait := parseRetryAfter(resp.Header)
+		t.limiter.Block(wait)
+
+		// Give up and surface the 429 when waiting would outlive the request
+		if attempt >= maxRateLimitRetries {
+			return resp, nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, nil
+		}
+		if req.Body != nil {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// RateLimitStats returns the state of the rate limiter used by the client
+func (c *Client) RateLimitStats() RateLimitStats {
+	return c.rateLimiter.Stats()
+}
@@ -0,0 +1,81 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitTransport_RetriesAfter429(t *testing.T) {
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-Rate-Limit", "700")
+		w.Header().Set("X-Rate-Limit-Remaining", "650")
+		fmt.Fprint(w, `{"user":{"id":1}}`)
+	}))
+
+	require.NoError(t, client.TestConnection(context.Background()))
+	assert.Equal(t, 2, calls)
+
+	stats := client.RateLimitStats()
+	assert.Equal(t, int64(1), stats.Throttled)
+	assert.Equal(t, 700, stats.Limit)
+	assert.Equal(t, 650, stats.Remaining)
+}
+
+func TestRateLimitTransport_GivesUpPastDeadline(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Error(t, client.TestConnection(ctx))
+	assert.False(t, client.RateLimitStats().BlockedUntil.IsZero())
+}
+
+func TestRateLimitTransport_WaitsOutsideTimeout(t *testing.T) {
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"user":{"id":1}}`)
+	}), WithTimeout(300*time.Millisecond))
+
+	// The one second wait for the rate limit does not count towards the timeout
+	require.NoError(t, client.TestConnection(context.Background()))
+	assert.Equal(t, 2, calls)
+}
+
+func TestRateLimiter_WaitHonorsContext(t *testing.T) {
+	limiter := NewRateLimiter(60)
+	limiter.Block(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	header := http.Header{}
+	assert.Equal(t, defaultRetryAfter, parseRetryAfter(header))
+
+	header.Set("Retry-After", "42")
+	assert.Equal(t, 42*time.Second, parseRetryAfter(header))
+}
@@ -0,0 +1,105 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetries retries quickly so tests do not sleep
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, IdempotentOnly: true}
+
+func TestRequest_RetriesServerErrors(t *testing.T) {
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"user":{"id":1}}`)
+	}))
+	client.retryPolicy = fastRetries
+
+	require.NoError(t, client.TestConnection(context.Background()))
+	assert.Equal(t, 3, calls)
+}
+
+func TestRequest_TimeoutPerAttempt(t *testing.T) {
+	var calls int32
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		fmt.Fprint(w, `{"user":{"id":1}}`)
+	}), WithTimeout(100*time.Millisecond))
+	client.retryPolicy = fastRetries
+
+	// The first attempt times out and the retry gets a fresh timeout
+	start := time.Now()
+	require.NoError(t, client.TestConnection(context.Background()))
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRequest_GivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	client.retryPolicy = fastRetries
+
+	assert.Error(t, client.TestConnection(context.Background()))
+	assert.Equal(t, 3, calls)
+}
+
+func TestRequest_DoesNotRetryNonIdempotent(t *testing.T) {
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	client.retryPolicy = fastRetries
+
+	_, err := client.request(context.Background(), "POST", "/tickets.json", strings.NewReader(`{}`))
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRequest_DoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	client.retryPolicy = fastRetries
+
+	assert.Error(t, client.TestConnection(context.Background()))
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		delay := policy.backoff(2)
+		assert.True(t, delay > 100*time.Millisecond && delay <= 200*time.Millisecond)
+	}
+}
@@ -0,0 +1,104 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Satisfaction rating scores. Filters such as received or good_with_comment
+// are also accepted by SatisfactionRatingOptions.Score.
+const (
+	SatisfactionScoreOffered   = "offered"
+	SatisfactionScoreUnoffered = "unoffered"
+	SatisfactionScoreGood      = "good"
+	SatisfactionScoreBad       = "bad"
+)
+
+// SatisfactionRating represents a customer satisfaction rating of a ticket
+type SatisfactionRating struct {
+	ID          int64   `json:"id"`
+	URL         string  `json:"url"`
+	AssigneeID  *int64  `json:"assignee_id,omitempty"`
+	GroupID     *int64  `json:"group_id,omitempty"`
+	RequesterID int64   `json:"requester_id"`
+	TicketID    int64   `json:"ticket_id"`
+	Score       string  `json:"score"`
+	Comment     *string `json:"comment,omitempty"`
+	Reason      *string `json:"reason,omitempty"`
+	ReasonID    *int64  `json:"reason_id,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+// SatisfactionRatingsResponse represents the response from satisfaction ratings API
+type SatisfactionRatingsResponse struct {
+	SatisfactionRatings []SatisfactionRating `json:"satisfaction_ratings"`
+	Meta                *Meta                `json:"meta,omitempty"`
+	Links               *Links               `json:"links,omitempty"`
+}
+
+// SatisfactionRatingOptions filters satisfaction ratings by score and by
+// creation time; zero values are not sent
+type SatisfactionRatingOptions struct {
+	Score     string
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// params returns the options as query parameters
+func (o SatisfactionRatingOptions) params() map[string]string {
+	params := map[string]string{}
+	if o.Score != "" {
+		params["score"] = o.Score
+	}
+	if !o.StartTime.IsZero() {
+		params["start_time"] = strconv.FormatInt(o.StartTime.Unix(), 10)
+	}
+	if !o.EndTime.IsZero() {
+		params["end_time"] = strconv.FormatInt(o.EndTime.Unix(), 10)
+	}
+	return params
+}
+
+// Offered reports whether a survey was sent for the rating, answered or not
+func (r SatisfactionRating) Offered() bool {
+	return r.Score != SatisfactionScoreUnoffered
+}
+
+// CreatedTime parses the rating creation timestamp
+func (r SatisfactionRating) CreatedTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, r.CreatedAt)
+}
+
+// WalkSatisfactionRatings calls fn for every page of satisfaction ratings,
+// following cursor pagination until exhaustion or maxRows ratings (0 means no cap)
+func (c *Client) WalkSatisfactionRatings(ctx context.Context, opts SatisfactionRatingOptions, maxRows int, fn func(ratings []SatisfactionRating) error) error {
+	return c.walkPages(ctx, "/satisfaction_ratings.json", opts.params(), maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page SatisfactionRatingsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.SatisfactionRatings[:trimRows(len(page.SatisfactionRatings), remaining)]
+		if err := fn(rows); err != nil {
+			return nil, err
+		}
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+}
+
+// ListSatisfactionRatings retrieves satisfaction ratings across every page
+func (c *Client) ListSatisfactionRatings(ctx context.Context, opts SatisfactionRatingOptions, maxRows int) ([]SatisfactionRating, error) {
+	var ratings []SatisfactionRating
+	err := c.WalkSatisfactionRatings(ctx, opts, maxRows, func(page []SatisfactionRating) error {
+		ratings = append(ratings, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
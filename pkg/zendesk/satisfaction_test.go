@@ -0,0 +1,35 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSatisfactionRatings_Filters(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/satisfaction_ratings.json", r.URL.Path)
+		query := r.URL.Query()
+		assert.Equal(t, "bad", query.Get("score"))
+		assert.Equal(t, "1704067200", query.Get("start_time"))
+		assert.Empty(t, query.Get("end_time"))
+		if query.Get("page[after]") == "" {
+			fmt.Fprintf(w, `{"satisfaction_ratings":[{"id":1,"score":"bad"}],"meta":{"has_more":true},"links":{"next":"http://%s/api/v2/satisfaction_ratings.json?score=bad&start_time=1704067200&page[after]=c1"}}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"satisfaction_ratings":[{"id":2,"score":"bad","comment":"Too slow"}],"meta":{"has_more":false}}`)
+	}))
+
+	ratings, err := client.ListSatisfactionRatings(context.Background(), SatisfactionRatingOptions{
+		Score:     SatisfactionScoreBad,
+		StartTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, 0)
+	require.NoError(t, err)
+	require.Len(t, ratings, 2)
+	assert.Equal(t, "Too slow", *ratings[1].Comment)
+}
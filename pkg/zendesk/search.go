@@ -0,0 +1,195 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxSearchResults is the most results /search.json can return for one query
+const MaxSearchResults = 1000
+
+// Search result types accepted by the search export API
+const (
+	SearchTypeTicket       = "ticket"
+	SearchTypeUser         = "user"
+	SearchTypeOrganization = "organization"
+)
+
+// SearchResponse represents the response from search API
+type SearchResponse struct {
+	Results       []json.RawMessage `json:"results"`
+	Users         []User            `json:"users,omitempty"`
+	Groups        []Group           `json:"groups,omitempty"`
+	Organizations []Organization    `json:"organizations,omitempty"`
+	Count         *int              `json:"count,omitempty"`
+	NextPage      *string           `json:"next_page,omitempty"`
+	PreviousPage  *string           `json:"previous_page,omitempty"`
+	Meta          *Meta             `json:"meta,omitempty"`
+	Links         *Links            `json:"links,omitempty"`
+}
+
+// SearchResults holds search results decoded by result type
+type SearchResults struct {
+	Tickets       []Ticket       `json:"tickets"`
+	Users         []User         `json:"users"`
+	Organizations []Organization `json:"organizations"`
+	// Count is the total number of matches reported by Zendesk
+	Count int `json:"count"`
+	// Sideloads holds records side-loaded with ticket results
+	Sideloads Sideloads `json:"-"`
+}
+
+// Len returns the number of decoded results
+func (r *SearchResults) Len() int {
+	return len(r.Tickets) + len(r.Users) + len(r.Organizations)
+}
+
+// add decodes a raw result according to its result_type
+func (r *SearchResults) add(raw json.RawMessage) error {
+	var header struct {
+		ResultType string `json:"result_type"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return fmt.Errorf("failed to decode search result: %w", err)
+	}
+
+	switch header.ResultType {
+	case SearchTypeTicket:
+		var ticket Ticket
+		if err := json.Unmarshal(raw, &ticket); err != nil {
+			return fmt.Errorf("failed to decode ticket result: %w", err)
+		}
+		r.Tickets = append(r.Tickets, ticket)
+	case SearchTypeUser:
+		var user User
+		if err := json.Unmarshal(raw, &user); err != nil {
+			return fmt.Errorf("failed to decode user result: %w", err)
+		}
+		r.Users = append(r.Users, user)
+	case SearchTypeOrganization:
+		var org Organization
+		if err := json.Unmarshal(raw, &org); err != nil {
+			return fmt.Errorf("failed to decode organization result: %w", err)
+		}
+		r.Organizations = append(r.Organizations, org)
+	}
+	// Other result types (groups, topics, ...) are not modeled and are skipped
+	return nil
+}
+
+// addPage decodes up to remaining results of a page (remaining <= 0 means no limit)
+func (r *SearchResults) addPage(results []json.RawMessage, remaining int) (int, error) {
+	results = results[:trimRows(len(results), remaining)]
+	for _, raw := range results {
+		if err := r.add(raw); err != nil {
+			return 0, err
+		}
+	}
+	return len(results), nil
+}
+
+// Search runs a query using Zendesk search syntax. When resultType is set the
+// query is restricted to that type and, if it matches more than
+// MaxSearchResults records, the search export API is used to read past the
+// limit. maxRows caps the number of results (0 means no cap). include names
+// records to side-load with ticket results; the export API does not side-load.
+func (c *Client) Search(ctx context.Context, query, resultType string, maxRows int, include ...string) (*SearchResults, error) {
+	fullQuery := query
+	if resultType != "" && !strings.Contains(query, "type:") {
+		fullQuery = fmt.Sprintf("type:%s %s", resultType, query)
+	}
+
+	params := map[string]string{
+		"query":    fullQuery,
+		"per_page": strconv.Itoa(MaxPageSize),
+	}
+	if len(include) > 0 {
+		params["include"] = fmt.Sprintf("tickets(%s)", strings.Join(include, ","))
+	}
+	endpoint := buildEndpoint("/search.json", params)
+
+	results := &SearchResults{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		remaining := 0
+		if maxRows > 0 {
+			remaining = maxRows - results.Len()
+		}
+
+		page, err := c.getSearchPage(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if page.Count != nil {
+			results.Count = *page.Count
+		}
+
+		// Offset pagination stops at MaxSearchResults, so switch to the export API
+		exportable := resultType != "" && (maxRows == 0 || maxRows > MaxSearchResults)
+		if results.Len() == 0 && exportable && results.Count > MaxSearchResults {
+			return c.searchExport(ctx, query, resultType, maxRows, results.Count)
+		}
+
+		if _, err := results.addPage(page.Results, remaining); err != nil {
+			return nil, err
+		}
+		results.Sideloads.add(page.Users, page.Groups, page.Organizations)
+		if maxRows > 0 && results.Len() >= maxRows {
+			return results, nil
+		}
+		if page.NextPage == nil || *page.NextPage == "" {
+			return results, nil
+		}
+
+		endpoint, err = c.relativeEndpoint(*page.NextPage)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getSearchPage requests and decodes a single search page
+func (c *Client) getSearchPage(ctx context.Context, endpoint string) (*SearchResponse, error) {
+	resp, err := c.request(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// searchExport reads every result of a single-type query from /search/export.json
+func (c *Client) searchExport(ctx context.Context, query, resultType string, maxRows, count int) (*SearchResults, error) {
+	results := &SearchResults{Count: count}
+	params := map[string]string{
+		"query":        query,
+		"filter[type]": resultType,
+	}
+	err := c.walkPages(ctx, "/search/export.json", params, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page SearchResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows, err := results.addPage(page.Results, remaining)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult{Rows: rows, Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,53 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch_DecodesResultTypes(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/search.json", r.URL.Path)
+		assert.Equal(t, "acme", r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"results":[
+			{"result_type":"ticket","id":1,"status":"open"},
+			{"result_type":"user","id":2,"name":"Jane"},
+			{"result_type":"organization","id":3,"name":"Acme"},
+			{"result_type":"group","id":4}
+		],"count":4}`)
+	}))
+
+	results, err := client.Search(context.Background(), "acme", "", 0)
+	require.NoError(t, err)
+	require.Len(t, results.Tickets, 1)
+	require.Len(t, results.Users, 1)
+	require.Len(t, results.Organizations, 1)
+	assert.Equal(t, "Jane", results.Users[0].Name)
+	assert.Equal(t, 4, results.Count)
+}
+
+func TestSearch_SwitchesToExport(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/search.json":
+			assert.Equal(t, "type:ticket status:open", r.URL.Query().Get("query"))
+			fmt.Fprint(w, `{"results":[{"result_type":"ticket","id":1}],"count":1500}`)
+		case "/api/v2/search/export.json":
+			assert.Equal(t, "ticket", r.URL.Query().Get("filter[type]"))
+			assert.Equal(t, "status:open", r.URL.Query().Get("query"))
+			fmt.Fprint(w, `{"results":[{"result_type":"ticket","id":1},{"result_type":"ticket","id":2}],"meta":{"has_more":false}}`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+
+	results, err := client.Search(context.Background(), "status:open", SearchTypeTicket, 0)
+	require.NoError(t, err)
+	assert.Len(t, results.Tickets, 2)
+	assert.Equal(t, 1500, results.Count)
+}
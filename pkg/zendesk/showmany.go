@@ -0,0 +1,122 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxShowManyIDs is the largest number of ids a show_many request accepts
+const MaxShowManyIDs = 100
+
+// idChunks splits ids into comma separated lists of at most MaxShowManyIDs
+// ids, skipping duplicates
+func idChunks(ids []int64) []string {
+	seen := make(map[int64]bool, len(ids))
+	var chunks []string
+	var chunk []string
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		chunk = append(chunk, strconv.FormatInt(id, 10))
+		if len(chunk) == MaxShowManyIDs {
+			chunks = append(chunks, strings.Join(chunk, ","))
+			chunk = nil
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, strings.Join(chunk, ","))
+	}
+	return chunks
+}
+
+// ShowManyTickets retrieves tickets by id, MaxShowManyIDs per request.
+// Tickets that do not exist are left out.
+func (c *Client) ShowManyTickets(ctx context.Context, ids []int64) ([]Ticket, error) {
+	var tickets []Ticket
+	for _, chunk := range idChunks(ids) {
+		resp, err := c.request(ctx, "GET", buildEndpoint("/tickets/show_many.json", map[string]string{"ids": chunk}), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result TicketsResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		tickets = append(tickets, result.Tickets...)
+	}
+	return tickets, nil
+}
+
+// ShowManyTicketsWithMetrics retrieves tickets by id with their metrics
+// side-loaded through include=metric_sets, MaxShowManyIDs per request
+func (c *Client) ShowManyTicketsWithMetrics(ctx context.Context, ids []int64) ([]Ticket, []TicketMetric, error) {
+	var tickets []Ticket
+	var metrics []TicketMetric
+	for _, chunk := range idChunks(ids) {
+		params := map[string]string{"ids": chunk, "include": "metric_sets"}
+		resp, err := c.request(ctx, "GET", buildEndpoint("/tickets/show_many.json", params), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var result TicketsResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		tickets = append(tickets, result.Tickets...)
+		metrics = append(metrics, result.MetricSets...)
+	}
+	return tickets, metrics, nil
+}
+
+// ShowManyUsers retrieves users by id, MaxShowManyIDs per request.
+// Users that do not exist are left out.
+func (c *Client) ShowManyUsers(ctx context.Context, ids []int64) ([]User, error) {
+	var users []User
+	for _, chunk := range idChunks(ids) {
+		resp, err := c.request(ctx, "GET", buildEndpoint("/users/show_many.json", map[string]string{"ids": chunk}), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result UsersResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		users = append(users, result.Users...)
+	}
+	return users, nil
+}
+
+// ShowManyOrganizations retrieves organizations by id, MaxShowManyIDs per
+// request. Organizations that do not exist are left out.
+func (c *Client) ShowManyOrganizations(ctx context.Context, ids []int64) ([]Organization, error) {
+	var orgs []Organization
+	for _, chunk := range idChunks(ids) {
+		resp, err := c.request(ctx, "GET", buildEndpoint("/organizations/show_many.json", map[string]string{"ids": chunk}), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result OrganizationsResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		orgs = append(orgs, result.Organizations...)
+	}
+	return orgs, nil
+}
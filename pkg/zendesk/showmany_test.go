@@ -0,0 +1,78 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowManyTickets_Chunks(t *testing.T) {
+	var requests []string
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets/show_many.json", r.URL.Path)
+		ids := r.URL.Query().Get("ids")
+		requests = append(requests, ids)
+		fmt.Fprintf(w, `{"tickets":[{"id":%s}]}`, strings.Split(ids, ",")[0])
+	}))
+
+	ids := make([]int64, 0, 151)
+	for id := int64(1); id <= 150; id++ {
+		ids = append(ids, id)
+	}
+	ids = append(ids, 1)
+
+	tickets, err := client.ShowManyTickets(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Len(t, strings.Split(requests[0], ","), MaxShowManyIDs)
+	assert.Len(t, strings.Split(requests[1], ","), 50)
+	assert.Equal(t, int64(101), tickets[1].ID)
+}
+
+func TestShowManyTicketsWithMetrics(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/tickets/show_many.json", r.URL.Path)
+		assert.Equal(t, "1,2", r.URL.Query().Get("ids"))
+		assert.Equal(t, "metric_sets", r.URL.Query().Get("include"))
+		fmt.Fprint(w, `{"tickets":[{"id":1},{"id":2}],"metric_sets":[{"id":11,"ticket_id":1},{"id":12,"ticket_id":2}]}`)
+	}))
+
+	tickets, metrics, err := client.ShowManyTicketsWithMetrics(context.Background(), []int64{1, 2})
+	require.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	require.Len(t, metrics, 2)
+	assert.Equal(t, int64(2), metrics[1].TicketID)
+}
+
+func TestShowManyUsersAndOrganizations(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/users/show_many.json":
+			assert.Equal(t, "7,8", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"users":[{"id":7,"name":"Ana"},{"id":8,"name":"Ben"}]}`)
+		case "/api/v2/organizations/show_many.json":
+			assert.Equal(t, "3", r.URL.Query().Get("ids"))
+			fmt.Fprint(w, `{"organizations":[{"id":3,"name":"Acme"}]}`)
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+
+	users, err := client.ShowManyUsers(context.Background(), []int64{7, 8, 7})
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+
+	orgs, err := client.ShowManyOrganizations(context.Background(), []int64{3})
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", orgs[0].Name)
+
+	// No ids, no requests
+	users, err = client.ShowManyUsers(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
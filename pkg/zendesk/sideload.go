@@ -0,0 +1,94 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Records that can be side-loaded with tickets through include
+const (
+	IncludeUsers         = "users"
+	IncludeGroups        = "groups"
+	IncludeOrganizations = "organizations"
+)
+
+// Sideloads holds records side-loaded with tickets, keyed by id
+type Sideloads struct {
+	Users         map[int64]User
+	Groups        map[int64]Group
+	Organizations map[int64]Organization
+}
+
+// add merges side-loaded records of one page
+func (s *Sideloads) add(users []User, groups []Group, orgs []Organization) {
+	if s.Users == nil {
+		s.Users = map[int64]User{}
+		s.Groups = map[int64]Group{}
+		s.Organizations = map[int64]Organization{}
+	}
+	for _, user := range users {
+		s.Users[user.ID] = user
+	}
+	for _, group := range groups {
+		s.Groups[group.ID] = group
+	}
+	for _, org := range orgs {
+		s.Organizations[org.ID] = org
+	}
+}
+
+// ListTicketsWithSideloads retrieves tickets with the related records named
+// by include (IncludeUsers, IncludeGroups, IncludeOrganizations) side-loaded,
+// saving one request per related record
+func (c *Client) ListTicketsWithSideloads(ctx context.Context, params map[string]string, include []string, maxRows int) ([]Ticket, *Sideloads, error) {
+	query := map[string]string{}
+	for k, v := range params {
+		query[k] = v
+	}
+	if len(include) > 0 {
+		query["include"] = strings.Join(include, ",")
+	}
+
+	var tickets []Ticket
+	sideloads := &Sideloads{}
+	err := c.walkPages(ctx, "/tickets.json", query, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page TicketsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Tickets[:trimRows(len(page.Tickets), remaining)]
+		tickets = append(tickets, rows...)
+		sideloads.add(page.Users, page.Groups, page.Organizations)
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return tickets, sideloads, nil
+}
+
+// GetTicket retrieves a single ticket with the related records named by
+// include side-loaded
+func (c *Client) GetTicket(ctx context.Context, ticketID int64, include ...string) (*Ticket, *Sideloads, error) {
+	params := map[string]string{}
+	if len(include) > 0 {
+		params["include"] = strings.Join(include, ",")
+	}
+	resp, err := c.request(ctx, "GET", buildEndpoint(fmt.Sprintf("/tickets/%d.json", ticketID), params), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var result TicketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	sideloads := &Sideloads{}
+	sideloads.add(result.Users, result.Groups, result.Organizations)
+	return &result.Ticket, sideloads, nil
+}
@@ -0,0 +1,41 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTicketsWithSideloads(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "users,organizations", r.URL.Query().Get("include"))
+		if r.URL.Query().Get("page[after]") == "" {
+			fmt.Fprintf(w, `{"tickets":[{"id":1,"requester_id":7}],"users":[{"id":7,"name":"Ana"}],"meta":{"has_more":true},"links":{"next":"http://%s/api/v2/tickets.json?include=users,organizations&page[after]=c1"}}`, r.Host)
+			return
+		}
+		fmt.Fprint(w, `{"tickets":[{"id":2,"requester_id":8,"organization_id":3}],"users":[{"id":8,"name":"Ben"}],"organizations":[{"id":3,"name":"Acme"}],"meta":{"has_more":false}}`)
+	}))
+
+	tickets, sideloads, err := client.ListTicketsWithSideloads(context.Background(), nil, []string{IncludeUsers, IncludeOrganizations}, 0)
+	require.NoError(t, err)
+	require.Len(t, tickets, 2)
+	assert.Equal(t, "Ana", sideloads.Users[7].Name)
+	assert.Equal(t, "Ben", sideloads.Users[8].Name)
+	assert.Equal(t, "Acme", sideloads.Organizations[3].Name)
+}
+
+func TestSearch_Sideloads(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tickets(users,groups)", r.URL.Query().Get("include"))
+		fmt.Fprint(w, `{"results":[{"id":1,"result_type":"ticket","group_id":4}],"groups":[{"id":4,"name":"Billing"}],"count":1}`)
+	}))
+
+	results, err := client.Search(context.Background(), "status:open", SearchTypeTicket, 0, IncludeUsers, IncludeGroups)
+	require.NoError(t, err)
+	require.Len(t, results.Tickets, 1)
+	assert.Equal(t, "Billing", results.Sideloads.Groups[4].Name)
+}
@@ -0,0 +1,140 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Ticket metric event types
+const (
+	MetricEventApplySLA     = "apply_sla"
+	MetricEventActivate     = "activate"
+	MetricEventPause        = "pause"
+	MetricEventFulfill      = "fulfill"
+	MetricEventBreach       = "breach"
+	MetricEventMeasure      = "measure"
+	MetricEventUpdateStatus = "update_status"
+)
+
+// SLAPolicyRef identifies the SLA policy applied by an apply_sla event
+type SLAPolicyRef struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// SLAEventDetails describes the SLA target applied by an apply_sla event
+type SLAEventDetails struct {
+	Target        int64        `json:"target"`
+	BusinessHours bool         `json:"business_hours"`
+	Policy        SLAPolicyRef `json:"policy"`
+}
+
+// TicketMetricEvent represents a change to one of a ticket's SLA metrics
+type TicketMetricEvent struct {
+	ID         int64            `json:"id"`
+	TicketID   int64            `json:"ticket_id"`
+	Metric     string           `json:"metric"`
+	InstanceID int64            `json:"instance_id"`
+	Type       string           `json:"type"`
+	Time       time.Time        `json:"time"`
+	SLA        *SLAEventDetails `json:"sla,omitempty"`
+	Status     *MetricDuration  `json:"status,omitempty"`
+	Deleted    bool             `json:"deleted,omitempty"`
+}
+
+// IncrementalTicketMetricEventsResponse represents a page of the ticket metric event export
+type IncrementalTicketMetricEventsResponse struct {
+	TicketMetricEvents []TicketMetricEvent `json:"ticket_metric_events"`
+	NextPage           *string             `json:"next_page,omitempty"`
+	EndTime            int64               `json:"end_time"`
+	Count              int                 `json:"count"`
+	EndOfStream        bool                `json:"end_of_stream"`
+}
+
+// SLAPolicyMetric is a single target of an SLA policy
+type SLAPolicyMetric struct {
+	Priority      string `json:"priority"`
+	Metric        string `json:"metric"`
+	Target        int64  `json:"target"`
+	BusinessHours bool   `json:"business_hours"`
+}
+
+// SLAPolicy represents a Zendesk SLA policy
+type SLAPolicy struct {
+	ID            int64             `json:"id"`
+	URL           string            `json:"url"`
+	Title         string            `json:"title"`
+	Description   string            `json:"description,omitempty"`
+	Position      int               `json:"position"`
+	PolicyMetrics []SLAPolicyMetric `json:"policy_metrics"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// SLAPoliciesResponse represents the response from SLA policies API
+type SLAPoliciesResponse struct {
+	SLAPolicies []SLAPolicy `json:"sla_policies"`
+	NextPage    *string     `json:"next_page,omitempty"`
+	Count       *int        `json:"count,omitempty"`
+}
+
+// IncrementalTicketMetricEvents walks the time-based ticket metric event
+// export, calling fn once per page, and returns the checkpoint to resume from.
+// Deleted events (e.g. cancelled breaches) are kept so callers can reconcile
+// them, as are breaches scheduled after opts.EndTime.
+func (c *Client) IncrementalTicketMetricEvents(ctx context.Context, opts IncrementalOptions, fn func(events []TicketMetricEvent) error) (*IncrementalCheckpoint, error) {
+	opts.Cursor = ""
+	endpoint := incrementalEndpoint("/incremental/ticket_metric_events.json", opts)
+	return c.walkIncremental(ctx, endpoint, opts, func(body io.Reader, remaining int) (*incrementalPage, error) {
+		var page IncrementalTicketMetricEventsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		// An empty page also marks the end of this export
+		result := &incrementalPage{
+			EndOfStream: page.EndOfStream || len(page.TicketMetricEvents) == 0,
+			EndTime:     page.EndTime,
+		}
+		if page.NextPage != nil {
+			result.NextURL = *page.NextPage
+		}
+
+		rows := make([]TicketMetricEvent, 0, len(page.TicketMetricEvents))
+		for _, event := range page.TicketMetricEvents {
+			if timeAfter(event.Time, opts.EndTime) {
+				// Breach events carry their scheduled time and do not end the export
+				if event.Type == MetricEventBreach {
+					rows = append(rows, event)
+					continue
+				}
+				result.PastEnd = true
+				continue
+			}
+			rows = append(rows, event)
+		}
+		rows = rows[:trimRows(len(rows), remaining)]
+		result.Rows = len(rows)
+		return result, fn(rows)
+	})
+}
+
+// ListSLAPolicies retrieves every SLA policy of the account
+func (c *Client) ListSLAPolicies(ctx context.Context) ([]SLAPolicy, error) {
+	resp, err := c.request(ctx, "GET", "/slas/policies.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result SLAPoliciesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.SLAPolicies, nil
+}
@@ -0,0 +1,208 @@
+package zendesk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ticket field types with typed decoding; other custom field types such as
+// textarea or regexp decode as text
+const (
+	FieldTypeText        = "text"
+	FieldTypeTextarea    = "textarea"
+	FieldTypeInteger     = "integer"
+	FieldTypeDecimal     = "decimal"
+	FieldTypeDate        = "date"
+	FieldTypeCheckbox    = "checkbox"
+	FieldTypeTagger      = "tagger"
+	FieldTypeMultiselect = "multiselect"
+)
+
+// CustomFieldValue is the raw value of a custom field on a ticket
+type CustomFieldValue struct {
+	ID    int64           `json:"id"`
+	Value json.RawMessage `json:"value"`
+}
+
+// CustomFieldOption is a choice of a dropdown or multiselect field
+type CustomFieldOption struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// TicketField represents the definition of a ticket field
+type TicketField struct {
+	ID                 int64               `json:"id"`
+	URL                string              `json:"url"`
+	Type               string              `json:"type"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description,omitempty"`
+	Position           int                 `json:"position"`
+	Active             bool                `json:"active"`
+	Required           bool                `json:"required"`
+	Removable          bool                `json:"removable"`
+	CustomFieldOptions []CustomFieldOption `json:"custom_field_options,omitempty"`
+	CreatedAt          string              `json:"created_at"`
+	UpdatedAt          string              `json:"updated_at"`
+}
+
+// TicketFieldsResponse represents the response from ticket fields API
+type TicketFieldsResponse struct {
+	TicketFields []TicketField `json:"ticket_fields"`
+	Meta         *Meta         `json:"meta,omitempty"`
+	Links        *Links        `json:"links,omitempty"`
+}
+
+// IsCustom reports whether the field is a custom field; system fields such
+// as subject or status cannot be removed
+func (f TicketField) IsCustom() bool {
+	return f.Removable
+}
+
+// CustomFieldValue returns the raw value of a custom field, or nil when the
+// ticket does not carry it
+func (t Ticket) CustomFieldValue(fieldID int64) json.RawMessage {
+	for _, field := range t.CustomFields {
+		if field.ID == fieldID {
+			return field.Value
+		}
+	}
+	return nil
+}
+
+// optionName returns the display name of a dropdown option tag
+func (f TicketField) optionName(tag string) string {
+	for _, option := range f.CustomFieldOptions {
+		if option.Value == tag {
+			return option.Name
+		}
+	}
+	return tag
+}
+
+// Decode converts a raw custom field value to the Go type of the field:
+// *int64 for integer, *float64 for decimal, *time.Time for date, *bool for
+// checkbox and *string otherwise. Dropdown values resolve to option names and
+// multiselect values to a comma separated list of names. Missing values
+// decode to a nil pointer of that type.
+func (f TicketField) Decode(raw json.RawMessage) (interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	null := len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+
+	switch f.Type {
+	case FieldTypeInteger:
+		if null {
+			return (*int64)(nil), nil
+		}
+		s, err := scalarString(raw)
+		if err != nil || s == "" {
+			return (*int64)(nil), err
+		}
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer value for field %d: %w", f.ID, err)
+		}
+		return &v, nil
+	case FieldTypeDecimal:
+		if null {
+			return (*float64)(nil), nil
+		}
+		s, err := scalarString(raw)
+		if err != nil || s == "" {
+			return (*float64)(nil), err
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid decimal value for field %d: %w", f.ID, err)
+		}
+		return &v, nil
+	case FieldTypeDate:
+		if null {
+			return (*time.Time)(nil), nil
+		}
+		s, err := scalarString(raw)
+		if err != nil || s == "" {
+			return (*time.Time)(nil), err
+		}
+		v, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date value for field %d: %w", f.ID, err)
+		}
+		return &v, nil
+	case FieldTypeCheckbox:
+		if null {
+			return (*bool)(nil), nil
+		}
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("invalid checkbox value for field %d: %w", f.ID, err)
+		}
+		return &v, nil
+	case FieldTypeMultiselect:
+		if null {
+			return (*string)(nil), nil
+		}
+		var tags []string
+		if err := json.Unmarshal(raw, &tags); err != nil {
+			return nil, fmt.Errorf("invalid multiselect value for field %d: %w", f.ID, err)
+		}
+		if len(tags) == 0 {
+			return (*string)(nil), nil
+		}
+		names := make([]string, len(tags))
+		for i, tag := range tags {
+			names[i] = f.optionName(tag)
+		}
+		v := strings.Join(names, ", ")
+		return &v, nil
+	default:
+		if null {
+			return (*string)(nil), nil
+		}
+		v, err := scalarString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for field %d: %w", f.ID, err)
+		}
+		if v == "" {
+			return (*string)(nil), nil
+		}
+		if f.Type == FieldTypeTagger {
+			v = f.optionName(v)
+		}
+		return &v, nil
+	}
+}
+
+// scalarString returns a JSON string as is and other scalars in their JSON form
+func scalarString(raw json.RawMessage) (string, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+	return string(raw), nil
+}
+
+// ListTicketFields retrieves every ticket field definition, system fields included
+func (c *Client) ListTicketFields(ctx context.Context) ([]TicketField, error) {
+	var fields []TicketField
+	err := c.walkPages(ctx, "/ticket_fields.json", nil, 0, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page TicketFieldsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		fields = append(fields, page.TicketFields...)
+		return &pageResult{Rows: len(page.TicketFields), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,69 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketField_Decode(t *testing.T) {
+	options := []CustomFieldOption{{Name: "Hardware", Value: "hw"}, {Name: "Software", Value: "sw"}}
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	text := func(s string) *string { return &s }
+	integer := func(v int64) *int64 { return &v }
+	decimal := func(v float64) *float64 { return &v }
+	boolean := func(v bool) *bool { return &v }
+
+	tests := []struct {
+		fieldType string
+		raw       string
+		want      interface{}
+	}{
+		{FieldTypeText, `"note"`, text("note")},
+		{FieldTypeText, `null`, (*string)(nil)},
+		{FieldTypeInteger, `"42"`, integer(42)},
+		{FieldTypeInteger, `42`, integer(42)},
+		{FieldTypeInteger, `null`, (*int64)(nil)},
+		{FieldTypeDecimal, `"1.5"`, decimal(1.5)},
+		{FieldTypeDate, `"2024-03-01"`, &date},
+		{FieldTypeDate, ``, (*time.Time)(nil)},
+		{FieldTypeCheckbox, `true`, boolean(true)},
+		{FieldTypeTagger, `"sw"`, text("Software")},
+		{FieldTypeTagger, `"unknown"`, text("unknown")},
+		{FieldTypeMultiselect, `["hw","sw"]`, text("Hardware, Software")},
+		{FieldTypeMultiselect, `[]`, (*string)(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fieldType+" "+tt.raw, func(t *testing.T) {
+			field := TicketField{ID: 1, Type: tt.fieldType, CustomFieldOptions: options}
+			got, err := field.Decode(json.RawMessage(tt.raw))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTicketField_DecodeInvalid(t *testing.T) {
+	_, err := TicketField{ID: 1, Type: FieldTypeInteger}.Decode(json.RawMessage(`"abc"`))
+	assert.Error(t, err)
+}
+
+func TestListTicketFields(t *testing.T) {
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/ticket_fields.json", r.URL.Path)
+		fmt.Fprint(w, `{"ticket_fields":[{"id":1,"type":"subject","title":"Subject"},{"id":2,"type":"tagger","title":"Category","removable":true,"custom_field_options":[{"name":"Hardware","value":"hw"}]}],"meta":{"has_more":false}}`)
+	}))
+
+	fields, err := client.ListTicketFields(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fields, 2)
+	assert.False(t, fields[0].IsCustom())
+	assert.True(t, fields[1].IsCustom())
+	assert.Equal(t, "hw", fields[1].CustomFieldOptions[0].Value)
+}
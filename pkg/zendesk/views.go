@@ -0,0 +1,129 @@
+package zendesk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MaxViewCountIDs is the largest number of views count_many accepts per request
+const MaxViewCountIDs = 20
+
+// ViewColumn is a column shown by a view
+type ViewColumn struct {
+	ID    json.RawMessage `json:"id"`
+	Title string          `json:"title"`
+}
+
+// ViewExecution describes how a view lists its tickets
+type ViewExecution struct {
+	GroupBy    *string      `json:"group_by,omitempty"`
+	GroupOrder string       `json:"group_order,omitempty"`
+	SortBy     *string      `json:"sort_by,omitempty"`
+	SortOrder  string       `json:"sort_order,omitempty"`
+	Columns    []ViewColumn `json:"columns,omitempty"`
+}
+
+// View represents a Zendesk view
+type View struct {
+	ID          int64          `json:"id"`
+	URL         string         `json:"url"`
+	Title       string         `json:"title"`
+	Description *string        `json:"description,omitempty"`
+	Active      bool           `json:"active"`
+	Position    int            `json:"position"`
+	Execution   *ViewExecution `json:"execution,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at"`
+}
+
+// ViewsResponse represents the response from views API
+type ViewsResponse struct {
+	Views []View `json:"views"`
+	Meta  *Meta  `json:"meta,omitempty"`
+	Links *Links `json:"links,omitempty"`
+}
+
+// ViewCount is the number of tickets in a view. Counts are computed in the
+// background: a count that is not Fresh is being refreshed and Value may be
+// stale or missing.
+type ViewCount struct {
+	ViewID int64  `json:"view_id"`
+	URL    string `json:"url"`
+	Value  *int64 `json:"value"`
+	Pretty string `json:"pretty"`
+	Fresh  bool   `json:"fresh"`
+}
+
+// ViewCountsResponse represents the response from view count APIs
+type ViewCountsResponse struct {
+	ViewCounts []ViewCount `json:"view_counts"`
+}
+
+// ListViews retrieves every shared and personal view available to the user
+func (c *Client) ListViews(ctx context.Context) ([]View, error) {
+	var views []View
+	err := c.walkPages(ctx, "/views.json", nil, 0, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page ViewsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		views = append(views, page.Views...)
+		return &pageResult{Rows: len(page.Views), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// ListViewTickets retrieves the tickets of a view in view order, up to
+// maxRows tickets (0 means no cap)
+func (c *Client) ListViewTickets(ctx context.Context, viewID int64, maxRows int) ([]Ticket, error) {
+	var tickets []Ticket
+	path := fmt.Sprintf("/views/%d/tickets.json", viewID)
+	err := c.walkPages(ctx, path, nil, maxRows, func(body io.Reader, remaining int) (*pageResult, error) {
+		var page TicketsResponse
+		if err := json.NewDecoder(body).Decode(&page); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		rows := page.Tickets[:trimRows(len(page.Tickets), remaining)]
+		tickets = append(tickets, rows...)
+		return &pageResult{Rows: len(rows), Meta: page.Meta, Links: page.Links}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// CountViews retrieves the ticket counts of views, MaxViewCountIDs per request
+func (c *Client) CountViews(ctx context.Context, viewIDs []int64) ([]ViewCount, error) {
+	var counts []ViewCount
+	for start := 0; start < len(viewIDs); start += MaxViewCountIDs {
+		end := start + MaxViewCountIDs
+		if end > len(viewIDs) {
+			end = len(viewIDs)
+		}
+		ids := make([]string, 0, end-start)
+		for _, id := range viewIDs[start:end] {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+
+		resp, err := c.request(ctx, "GET", buildEndpoint("/views/count_many.json", map[string]string{"ids": strings.Join(ids, ",")}), nil)
+		if err != nil {
+			return nil, err
+		}
+		var result ViewCountsResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		counts = append(counts, result.ViewCounts...)
+	}
+	return counts, nil
+}
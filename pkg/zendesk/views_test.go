@@ -0,0 +1,34 @@
+package zendesk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountViews_Chunks(t *testing.T) {
+	var requests []string
+	client, _ := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/views/count_many.json", r.URL.Path)
+		ids := r.URL.Query().Get("ids")
+		requests = append(requests, ids)
+		fmt.Fprintf(w, `{"view_counts":[{"view_id":%s,"value":4,"fresh":true}]}`, strings.Split(ids, ",")[0])
+	}))
+
+	ids := make([]int64, 25)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+
+	counts, err := client.CountViews(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Len(t, strings.Split(requests[0], ","), MaxViewCountIDs)
+	assert.Equal(t, "21,22,23,24,25", requests[1])
+	assert.Equal(t, int64(21), counts[1].ViewID)
+}
@@ -7,6 +7,22 @@ export interface ZendeskConfig extends DataSourceJsonData {
   subdomain?: string;
   email?: string;
   apiToken?: string;
+  baseUrl?: string; // 自訂 API 基礎 URL（例如主機映射網域）
+  proxyUrl?: string; // HTTP(S) 代理
+  timeout?: number; // 每次請求嘗試的逾時（秒）
+  tlsAuth?: boolean; // TLS 用戶端憑證驗證
+  tlsAuthWithCACert?: boolean; // 使用自訂 CA 憑證驗證伺服器
+  tlsSkipVerify?: boolean; // 略過 TLS 驗證
+  serverName?: string; // 驗證憑證的伺服器名稱
+  authMode?: 'apiToken' | 'oauthToken' | 'oauthClientCredentials'; // 認證方式
+  oauthTokenUrl?: string; // OAuth 令牌端點
+  oauthClientId?: string; // OAuth 客戶端 ID
+  oauthScope?: string; // OAuth 範圍
+  retryMaxAttempts?: number; // 最大嘗試次數
+  retryBaseDelayMs?: number; // 初始重試延遲（毫秒）
+  retryMaxDelayMs?: number; // 最大重試延遲（毫秒）
+  retryJitter?: number; // 抖動比例 (0-1)
+  retryNonIdempotent?: boolean; // 允許重試非冪等請求
 }
 
 /**
@@ -14,6 +30,11 @@ export interface ZendeskConfig extends DataSourceJsonData {
  */
 export interface ZendeskSecureJsonData {
   apiToken?: string;
+  oauthAccessToken?: string;
+  oauthClientSecret?: string;
+  tlsCACert?: string;
+  tlsClientCert?: string;
+  tlsClientKey?: string;
 }
 
 /**
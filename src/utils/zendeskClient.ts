@@ -24,24 +24,42 @@ export class ZendeskClient {
   private email: string;
   private apiToken: string;
   private baseUrl: string;
+  private config: ZendeskConfig;
+  private secureJsonData: ZendeskSecureJsonData;
 
   constructor(config: ZendeskConfig, secureJsonData?: ZendeskSecureJsonData) {
     this.subdomain = config.subdomain || '';
     this.email = config.email || '';
     this.apiToken = secureJsonData?.apiToken || config.apiToken || '';
-    this.baseUrl = `https://${this.subdomain}.zendesk.com/api/v2`;
+    this.baseUrl = config.baseUrl
+      ? config.baseUrl.replace(/\/+$/, '')
+      : `https://${this.subdomain}.zendesk.com/api/v2`;
+    this.config = config;
+    this.secureJsonData = secureJsonData || {};
   }
 
   /**
-   * 構建認證頭
+   * 構建認證頭（依認證方式使用 API 令牌或 OAuth 令牌）
    */
-  private getAuthHeaders(): HeadersInit {
-    const credentials = `${this.email}/token:${this.apiToken}`;
-    const encoded = btoa(credentials);
-    return {
-      'Authorization': `Basic ${encoded}`,
-      'Content-Type': 'application/json',
-    };
+  private async getAuthHeaders(): Promise<HeadersInit> {
+    switch (this.config.authMode) {
+      case 'oauthToken':
+        return {
+          'Authorization': `Bearer ${this.secureJsonData.oauthAccessToken || ''}`,
+          'Content-Type': 'application/json',
+        };
+      case 'oauthClientCredentials':
+        // 客戶端密鑰不可送到瀏覽器，令牌只由後端交換
+        throw new Error('OAuth client credentials are only supported by the backend, use the backend health check');
+      default: {
+        const credentials = `${this.email}/token:${this.apiToken}`;
+        const encoded = btoa(credentials);
+        return {
+          'Authorization': `Basic ${encoded}`,
+          'Content-Type': 'application/json',
+        };
+      }
+    }
   }
 
   /**
@@ -53,7 +71,7 @@ export class ZendeskClient {
   ): Promise<T> {
     const url = `${this.baseUrl}${endpoint}`;
     const headers = {
-      ...this.getAuthHeaders(),
+      ...(await this.getAuthHeaders()),
       ...options.headers,
     };
 
@@ -315,7 +333,7 @@ export class ZendeskClient {
     try {
       // 嘗試獲取當前用戶信息來測試連接
       const response = await fetch(`${this.baseUrl}/users/me.json`, {
-        headers: this.getAuthHeaders(),
+        headers: await this.getAuthHeaders(),
       });
 
       if (response.ok) {